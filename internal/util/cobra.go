@@ -9,6 +9,10 @@ import (
 func LoadFlagsFromAltSources(cmd *cobra.Command, args []string) {
 	flags := cmd.Flags()
 
+	if hasConfigInEnvButNotFlag(cmd) {
+		_ = flags.Set("config", viper.GetViper().GetString("SHOUTRRR_CONFIG"))
+	}
+
 	if len(args) > 0 {
 		_ = flags.Set("url", args[0])
 
@@ -33,3 +37,11 @@ func hasURLInEnvButNotFlag(cmd *cobra.Command) bool {
 	s, _ := cmd.Flags().GetString("url")
 	return s == "" && viper.GetViper().GetString("SHOUTRRR_URL") != ""
 }
+
+func hasConfigInEnvButNotFlag(cmd *cobra.Command) bool {
+	if cmd.Flags().Lookup("config") == nil {
+		return false
+	}
+	s, _ := cmd.Flags().GetString("config")
+	return s == "" && viper.GetViper().GetString("SHOUTRRR_CONFIG") != ""
+}
@@ -23,6 +23,7 @@ type FieldInfo struct {
 	Base          int
 	Keys          []string
 	ItemSeparator rune
+	Secret        bool
 }
 
 // IsEnum returns whether a EnumFormatter has been assigned to the field and that it is of a suitable type
@@ -89,6 +90,12 @@ func getStructFieldInfo(structType r.Type, enums map[string]types.EnumFormatter)
 
 		if tag, ok := fieldDef.Tag.Lookup("url"); ok {
 			info.URLParts = ParseURLParts(tag)
+			// Values that are serialized as the URL password are always considered secret
+			info.Secret = info.IsURLPart(URLPassword)
+		}
+
+		if _, ok := fieldDef.Tag.Lookup("secret"); ok {
+			info.Secret = true
 		}
 
 		if tag, ok := fieldDef.Tag.Lookup("key"); ok {
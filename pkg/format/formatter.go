@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	r "reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -12,10 +13,13 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
-// GetServiceConfig returns the inner config of a service
+// GetServiceConfig returns the inner config of a service, or nil if the service does not have a config field
 func GetServiceConfig(service types.Service) types.ServiceConfig {
 	serviceValue := r.Indirect(r.ValueOf(service))
-	configField, _ := serviceValue.Type().FieldByName("config")
+	configField, found := serviceValue.Type().FieldByName("config")
+	if !found || configField.Type.Kind() != r.Ptr {
+		return nil
+	}
 	configRef := serviceValue.FieldByIndex(configField.Index)
 
 	var ourRef r.Value
@@ -49,6 +53,19 @@ func GetConfigFormat(config types.ServiceConfig) *ContainerNode {
 	return getRootNode(config)
 }
 
+// GetParamKeys returns the primary keys of the config props, sorted by name. These are the props that can be
+// overridden for a single send by passing them as params.
+func GetParamKeys(config types.ServiceConfig) []string {
+	var keys []string
+	for _, node := range GetConfigFormat(config).Items {
+		if field := node.Field(); len(field.Keys) > 0 && field.Keys[0] != "" {
+			keys = append(keys, strings.ToLower(field.Keys[0]))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // SetConfigField deserializes the inputValue and sets the field of a config to that value
 func SetConfigField(config r.Value, field FieldInfo, inputValue string) (valid bool, err error) {
 	configField := config.FieldByName(field.Name)
@@ -236,6 +236,12 @@ var _ = Describe("SetConfigField", func() {
 	})
 })
 
+var _ = Describe("GetParamKeys", func() {
+	It("should return the primary keys of the config props in order", func() {
+		Expect(GetParamKeys(&testStruct{})).To(Equal([]string{"signed", "str", "testenum"}))
+	})
+})
+
 func testSetAndFormat(tv reflect.Value, node Node, value string, prettyFormat string) {
 	field := node.Field()
 	_, _ = SetConfigField(tv, *field, value)
@@ -0,0 +1,62 @@
+package format
+
+import (
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/redact"
+)
+
+// GetSecretValues returns the non-empty values of the config fields that are tagged as secret, or are serialized as
+// the URL password
+func GetSecretValues(config types.ServiceConfig) []string {
+	if config == nil {
+		return nil
+	}
+
+	var secrets []string
+	for _, node := range getRootNode(config).Items {
+		if !node.Field().Secret {
+			continue
+		}
+		secrets = append(secrets, getNodeValues(node)...)
+	}
+	return secrets
+}
+
+func getNodeValues(node Node) []string {
+	switch n := node.(type) {
+	case *ValueNode:
+		if n.Value != "" {
+			return []string{n.Value}
+		}
+	case *ContainerNode:
+		var values []string
+		for _, item := range n.Items {
+			values = append(values, getNodeValues(item)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// RedactSecrets replaces the values of the secret fields in the node tree with redact.Placeholder
+func RedactSecrets(root *ContainerNode) {
+	for _, node := range root.Items {
+		if node.Field().Secret {
+			redactNode(node)
+		}
+	}
+}
+
+func redactNode(node Node) {
+	switch n := node.(type) {
+	case *ValueNode:
+		if n.Value != "" {
+			n.Value = redact.Placeholder
+			n.tokenType = StringToken
+		}
+	case *ContainerNode:
+		for _, item := range n.Items {
+			redactNode(item)
+		}
+	}
+}
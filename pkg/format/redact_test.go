@@ -0,0 +1,89 @@
+package format
+
+import (
+	"net/url"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	"github.com/containrrr/shoutrrr/pkg/util/redact"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testSecretConfig struct {
+	standard.EnumlessConfig
+	User     string   `url:"user"`
+	Password string   `url:"pass"`
+	Token    string   `url:"path" secret:""`
+	Keys     []string `key:"keys" secret:""`
+	Channel  string   `key:"channel"`
+}
+
+func (t *testSecretConfig) GetURL() *url.URL {
+	panic("not implemented")
+}
+
+func (t *testSecretConfig) SetURL(_ *url.URL) error {
+	panic("not implemented")
+}
+
+var _ = Describe("secret config fields", func() {
+	var config *testSecretConfig
+	BeforeEach(func() {
+		config = &testSecretConfig{
+			User:     "user",
+			Password: "hunter2",
+			Token:    "t0ken",
+			Keys:     []string{"key1", "key2"},
+			Channel:  "general",
+		}
+	})
+
+	It("should treat fields tagged as secret or serialized as the URL password as secret", func() {
+		fields := getStructFieldInfo(getRootNode(config).Type, nil)
+		secret := map[string]bool{}
+		for _, field := range fields {
+			secret[field.Name] = field.Secret
+		}
+		Expect(secret).To(Equal(map[string]bool{
+			"User":     false,
+			"Password": true,
+			"Token":    true,
+			"Keys":     true,
+			"Channel":  false,
+		}))
+	})
+
+	It("should return the values of the secret fields", func() {
+		Expect(GetSecretValues(config)).To(ConsistOf("hunter2", "t0ken", "key1", "key2"))
+	})
+
+	It("should skip empty secret values", func() {
+		config.Token = ""
+		config.Keys = nil
+		Expect(GetSecretValues(config)).To(ConsistOf("hunter2"))
+	})
+
+	It("should redact the secret values in a config tree", func() {
+		root := GetConfigFormat(config)
+		RedactSecrets(root)
+
+		values := map[string]string{}
+		for _, node := range root.Items {
+			if valueNode, isValue := node.(*ValueNode); isValue {
+				values[node.Field().Name] = valueNode.Value
+			}
+			if containerNode, isContainer := node.(*ContainerNode); isContainer {
+				for _, item := range containerNode.Items {
+					Expect(item.(*ValueNode).Value).To(Equal(redact.Placeholder))
+				}
+			}
+		}
+		Expect(values).To(Equal(map[string]string{
+			"User":     "user",
+			"Password": redact.Placeholder,
+			"Token":    redact.Placeholder,
+			"Channel":  "general",
+		}))
+	})
+})
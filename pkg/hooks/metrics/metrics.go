@@ -0,0 +1,77 @@
+// Package metrics implements a router hook that collects Prometheus metrics of the sends using each service
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+)
+
+const namespace = "shoutrrr"
+
+// Collector is a router hook that counts the sent and failed notifications, and observes the send durations, using
+// the service scheme as label. It needs to be registered with a Prometheus registry to expose the metrics.
+type Collector struct {
+	sent     *prometheus.CounterVec
+	failed   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewCollector creates a new collector using the default histogram buckets for the send durations
+func NewCollector() *Collector {
+	return NewCollectorWithBuckets(prometheus.DefBuckets)
+}
+
+// NewCollectorWithBuckets creates a new collector using the specified histogram buckets, in seconds, for the send
+// durations
+func NewCollectorWithBuckets(buckets []float64) *Collector {
+	return &Collector{
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_sent_total",
+			Help:      "Number of notifications successfully sent, by service.",
+		}, []string{"scheme"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_failed_total",
+			Help:      "Number of notifications that failed to send, by service.",
+		}, []string{"scheme"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "send_duration_seconds",
+			Help:      "Time spent sending notifications, including retries, by service.",
+			Buckets:   buckets,
+		}, []string{"scheme"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.sent.Describe(ch)
+	c.failed.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.sent.Collect(ch)
+	c.failed.Collect(ch)
+	c.duration.Collect(ch)
+}
+
+// BeforeSend implements router.Hook, and does nothing
+func (c *Collector) BeforeSend(ctx context.Context, _ router.SendInfo) context.Context {
+	return ctx
+}
+
+// AfterSend implements router.Hook, updating the metrics of the service
+func (c *Collector) AfterSend(_ context.Context, result router.SendResult) {
+	c.duration.WithLabelValues(result.Scheme).Observe(result.Duration.Seconds())
+	if result.Err != nil {
+		c.failed.WithLabelValues(result.Scheme).Inc()
+	} else {
+		c.sent.WithLabelValues(result.Scheme).Inc()
+	}
+}
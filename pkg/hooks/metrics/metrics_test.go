@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metrics Hook Suite")
+}
+
+var _ = Describe("the metrics collector", func() {
+	var collector *Collector
+
+	BeforeEach(func() {
+		collector = NewCollector()
+	})
+
+	It("should be registrable", func() {
+		Expect(prometheus.NewPedanticRegistry().Register(collector)).To(Succeed())
+	})
+
+	It("should count the sent and failed notifications by scheme", func() {
+		collector.AfterSend(context.Background(), router.SendResult{Scheme: "ntfy", Duration: time.Second})
+		collector.AfterSend(context.Background(), router.SendResult{Scheme: "ntfy", Duration: time.Second})
+		collector.AfterSend(context.Background(), router.SendResult{Scheme: "slack", Err: errors.New("failed")})
+
+		Expect(testutil.ToFloat64(collector.sent.WithLabelValues("ntfy"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(collector.failed.WithLabelValues("slack"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(collector.failed.WithLabelValues("ntfy"))).To(Equal(0.0))
+	})
+
+	It("should observe the send durations", func() {
+		expected := `
+# HELP shoutrrr_send_duration_seconds Time spent sending notifications, including retries, by service.
+# TYPE shoutrrr_send_duration_seconds histogram
+shoutrrr_send_duration_seconds_bucket{scheme="ntfy",le="1"} 0
+shoutrrr_send_duration_seconds_bucket{scheme="ntfy",le="5"} 1
+shoutrrr_send_duration_seconds_bucket{scheme="ntfy",le="+Inf"} 1
+shoutrrr_send_duration_seconds_sum{scheme="ntfy"} 2
+shoutrrr_send_duration_seconds_count{scheme="ntfy"} 1
+`
+		collector = NewCollectorWithBuckets([]float64{1, 5})
+		collector.AfterSend(context.Background(), router.SendResult{Scheme: "ntfy", Duration: 2 * time.Second})
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected), "shoutrrr_send_duration_seconds")).To(Succeed())
+	})
+})
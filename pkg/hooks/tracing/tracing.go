@@ -0,0 +1,62 @@
+// Package tracing implements a router hook that creates an OpenTelemetry span for every send using a service
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/containrrr/shoutrrr/internal/meta"
+	"github.com/containrrr/shoutrrr/pkg/router"
+)
+
+const instrumentationName = "github.com/containrrr/shoutrrr"
+
+// Attribute keys of the send spans
+const (
+	SchemeKey     = attribute.Key("shoutrrr.service.scheme")
+	URLKey        = attribute.Key("shoutrrr.service.url")
+	AttemptsKey   = attribute.Key("shoutrrr.send.attempts")
+	MessageIDsKey = attribute.Key("shoutrrr.send.message_ids")
+)
+
+// Hook is a router hook that creates a span for every send using a service, as a child of any span in the context of
+// the send. The span is recorded as an error if the send failed.
+type Hook struct {
+	tracer trace.Tracer
+}
+
+// NewHook creates a new tracing hook using the tracer provider, or the global provider if it is nil
+func NewHook(provider trace.TracerProvider) *Hook {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Hook{
+		tracer: provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(meta.Version)),
+	}
+}
+
+// BeforeSend implements router.Hook, starting the span of the send
+func (h *Hook) BeforeSend(ctx context.Context, info router.SendInfo) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "shoutrrr.send "+info.Scheme,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(SchemeKey.String(info.Scheme), URLKey.String(info.URL)))
+	return ctx
+}
+
+// AfterSend implements router.Hook, ending the span of the send
+func (h *Hook) AfterSend(ctx context.Context, result router.SendResult) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(AttemptsKey.Int(result.Attempts))
+	if len(result.MessageIDs) > 0 {
+		span.SetAttributes(MessageIDsKey.StringSlice(result.MessageIDs))
+	}
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTracing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tracing Hook Suite")
+}
+
+var _ = Describe("the tracing hook", func() {
+	var recorder *tracetest.SpanRecorder
+	var provider *sdktrace.TracerProvider
+	var hook *Hook
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		hook = NewHook(provider)
+	})
+
+	send := func(ctx context.Context, result router.SendResult) {
+		ctx = hook.BeforeSend(ctx, router.SendInfo{Scheme: result.Scheme, URL: result.URL})
+		hook.AfterSend(ctx, result)
+	}
+
+	It("should record a span for every send", func() {
+		send(context.Background(), router.SendResult{Scheme: "ntfy", URL: "ntfy://ntfy.sh/topic", Attempts: 2})
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name()).To(Equal("shoutrrr.send ntfy"))
+		Expect(spans[0].SpanKind()).To(Equal(trace.SpanKindClient))
+		Expect(spans[0].Attributes()).To(ContainElements(
+			SchemeKey.String("ntfy"),
+			URLKey.String("ntfy://ntfy.sh/topic"),
+			AttemptsKey.Int(2),
+		))
+		Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+	})
+
+	It("should record the error of failed sends", func() {
+		send(context.Background(), router.SendResult{Scheme: "ntfy", Err: errors.New("send failed")})
+
+		span := recorder.Ended()[0]
+		Expect(span.Status().Code).To(Equal(codes.Error))
+		Expect(span.Status().Description).To(Equal("send failed"))
+		Expect(span.Events()).To(HaveLen(1))
+	})
+
+	It("should use the span of the context as parent", func() {
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+		send(ctx, router.SendResult{Scheme: "ntfy"})
+		parent.End()
+
+		Expect(recorder.Ended()[0].Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	})
+
+	It("should trace the sends of a router", func() {
+		serviceRouter, err := router.New(nil, "logger://")
+		Expect(err).NotTo(HaveOccurred())
+		serviceRouter.Hooks = []router.Hook{hook}
+
+		Expect(serviceRouter.Send("message", nil)).To(Equal([]error{nil}))
+		Expect(recorder.Ended()).To(HaveLen(1))
+		Expect(recorder.Ended()[0].Name()).To(Equal("shoutrrr.send logger"))
+	})
+})
@@ -0,0 +1,214 @@
+// Package outbox persists notifications that failed to send, so that they can be replayed at a later time
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+)
+
+const (
+	// DefaultBackoff is the delay before the first replay of a stored record
+	DefaultBackoff = time.Minute
+	// DefaultMaxBackoff is the upper limit of the delay between replays of a stored record
+	DefaultMaxBackoff = time.Hour
+)
+
+// Outbox wraps a ServiceRouter, storing the sends that failed for each service URL so that they can be replayed
+type Outbox struct {
+	router *router.ServiceRouter
+	store  *Store
+	logger t.StdLogger
+	now    func() time.Time
+	// Backoff is the delay before the first replay of a record, which is then doubled for every subsequent replay
+	Backoff time.Duration
+	// MaxBackoff is the upper limit of the delay between replays of a record
+	MaxBackoff time.Duration
+	// NewRouter creates the router used for replaying a record using its service URL. By default, it is derived from
+	// the router of the outbox, using the same HTTP client, template, timeout and retry policy.
+	NewRouter func(serviceURL string) (*router.ServiceRouter, error)
+}
+
+// New returns an outbox that sends using the router and stores failed sends in the store. The router may be nil if
+// the outbox is only used for replaying stored records, in which case they are replayed using the default settings.
+func New(sr *router.ServiceRouter, store *Store, logger t.StdLogger) *Outbox {
+	if logger == nil {
+		logger = util.DiscardLogger
+	}
+	newRouter := func(serviceURL string) (*router.ServiceRouter, error) {
+		return router.New(logger, serviceURL)
+	}
+	if sr != nil {
+		newRouter = func(serviceURL string) (*router.ServiceRouter, error) {
+			return sr.Derive(serviceURL)
+		}
+	}
+	return &Outbox{
+		router:     sr,
+		store:      store,
+		logger:     logger,
+		now:        time.Now,
+		Backoff:    DefaultBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		NewRouter:  newRouter,
+	}
+}
+
+// Store returns the store used by the outbox
+func (o *Outbox) Store() *Store {
+	return o.store
+}
+
+// Send sends the specified message using the routers services, storing the failed sends in the outbox
+func (o *Outbox) Send(message string, params *t.Params) []error {
+	return o.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends the specified message using the routers services, storing the failed sends in the outbox.
+// The returned errors are still reported, in the same order as the routers services. Nothing is stored if the message
+// was delivered according to the routers strategy, even if some of the services failed.
+func (o *Outbox) SendContext(ctx context.Context, message string, params *t.Params) []error {
+	errs := o.router.SendContext(ctx, message, params)
+	if o.router == nil || o.router.Delivered(errs) {
+		return errs
+	}
+
+	urls := o.router.ServiceURLs()
+	for i, err := range errs {
+		if err == nil || i >= len(urls) {
+			continue
+		}
+
+		record := Record{
+			URL:     urls[i],
+			Message: message,
+			Created: o.now(),
+		}
+		if params != nil {
+			record.Params = *params
+		}
+		o.recordFailure(&record, err)
+
+		if storeErr := o.store.Add(&record); storeErr != nil {
+			errs[i] = fmt.Errorf("%w (failed to store in outbox: %v)", err, storeErr)
+			continue
+		}
+		o.logger.Printf("Stored failed send in outbox as %v", record.ID)
+	}
+
+	return errs
+}
+
+// Replay sends the stored record with the specified ID, regardless of when its next attempt is scheduled.
+// If the send succeeds, the record is removed from the outbox, otherwise it is rescheduled.
+func (o *Outbox) Replay(ctx context.Context, id string) error {
+	record, err := o.store.Get(id)
+	if err != nil {
+		return err
+	}
+	return o.replay(ctx, record)
+}
+
+// ReplayDue sends all the stored records that are due for another attempt, returning the number of records that
+// were delivered. Failed sends are rescheduled, and only store errors are returned.
+func (o *Outbox) ReplayDue(ctx context.Context) (delivered int, err error) {
+	records, err := o.store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	now := o.now()
+	for _, record := range records {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		if record.NextAttempt.After(now) {
+			continue
+		}
+
+		err := o.replay(ctx, record)
+		if err == nil {
+			delivered++
+			continue
+		}
+		if _, isSendErr := err.(sendError); !isSendErr {
+			return delivered, err
+		}
+		o.logger.Printf("Failed to replay %v: %v", record.ID, err)
+	}
+
+	return delivered, nil
+}
+
+// Run replays the due records in the outbox every interval, until ctx is done
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if delivered, err := o.ReplayDue(ctx); err != nil && ctx.Err() == nil {
+			o.logger.Printf("Failed to replay outbox: %v", err)
+		} else if delivered > 0 {
+			o.logger.Printf("Delivered %d record(s) from the outbox", delivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Outbox) replay(ctx context.Context, record Record) error {
+	sendErr := o.sendRecord(ctx, record)
+	if sendErr == nil {
+		return o.store.Remove(record.ID)
+	}
+
+	o.recordFailure(&record, sendErr)
+	if err := o.store.Save(record); err != nil {
+		return err
+	}
+	return sendError{sendErr}
+}
+
+func (o *Outbox) sendRecord(ctx context.Context, record Record) error {
+	sr, err := o.NewRouter(record.URL)
+	if err != nil {
+		return err
+	}
+
+	params := record.Params
+	if params == nil {
+		params = t.Params{}
+	}
+	return sr.SendContext(ctx, record.Message, &params)[0]
+}
+
+// recordFailure updates the record with the failed attempt and schedules the next one
+func (o *Outbox) recordFailure(record *Record, err error) {
+	record.Attempts++
+	record.LastError = err.Error()
+
+	policy := retry.Policy{Backoff: o.Backoff, MaxBackoff: o.MaxBackoff}
+	delay := policy.Delay(record.Attempts)
+	if _, retryAfter := retry.IsTransient(err); retryAfter > delay {
+		delay = retryAfter
+	}
+	record.NextAttempt = o.now().Add(delay)
+}
+
+// sendError is returned by replay when the record could not be sent, as opposed to errors from the store
+type sendError struct {
+	error
+}
+
+func (se sendError) Unwrap() error {
+	return se.error
+}
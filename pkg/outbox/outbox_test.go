@@ -0,0 +1,191 @@
+package outbox_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containrrr/shoutrrr/pkg/outbox"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+const (
+	failingURL = "generic://failing.example/hook"
+	workingURL = "generic://working.example/hook"
+)
+
+var _ = Describe("the outbox", func() {
+	var store *outbox.Store
+	var failingStatus int
+
+	BeforeEach(func() {
+		var err error
+		store, err = outbox.NewStore(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+
+		failingStatus = http.StatusServiceUnavailable
+		httpmock.Activate()
+		httpmock.RegisterResponder("POST", "https://failing.example/hook", func(*http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(failingStatus, ""), nil
+		})
+		httpmock.RegisterResponder("POST", "https://working.example/hook", httpmock.NewStringResponder(200, ""))
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	When("sending a message", func() {
+		It("should store the failed sends for each service URL", func() {
+			sr, err := router.New(nil, workingURL, failingURL)
+			Expect(err).NotTo(HaveOccurred())
+			ob := outbox.New(sr, store, nil)
+
+			errs := ob.Send("message", &types.Params{"title": "title"})
+			Expect(errs[0]).NotTo(HaveOccurred())
+			Expect(errs[1]).To(HaveOccurred())
+
+			records, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].URL).To(Equal(failingURL))
+			Expect(records[0].Message).To(Equal("message"))
+			Expect(records[0].Params).To(Equal(types.Params{"title": "title"}))
+			Expect(records[0].Attempts).To(Equal(1))
+			Expect(records[0].LastError).NotTo(BeEmpty())
+			Expect(records[0].NextAttempt).To(BeTemporally(">", records[0].Created))
+		})
+
+		It("should not store anything if the message was delivered according to the strategy", func() {
+			sr, err := router.New(nil, failingURL, workingURL)
+			Expect(err).NotTo(HaveOccurred())
+			sr.Strategy = router.Failover
+			ob := outbox.New(sr, store, nil)
+
+			errs := ob.Send("message", nil)
+			Expect(errs[0]).To(HaveOccurred())
+			Expect(errs[1]).NotTo(HaveOccurred())
+
+			records, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+
+	When("replaying records", func() {
+		var ob *outbox.Outbox
+		BeforeEach(func() {
+			ob = outbox.New(nil, store, nil)
+			Expect(store.Add(&outbox.Record{URL: failingURL, Message: "due", Created: time.Now()})).To(Succeed())
+			Expect(store.Add(&outbox.Record{
+				URL:         failingURL,
+				Message:     "scheduled",
+				Created:     time.Now(),
+				NextAttempt: time.Now().Add(time.Hour),
+			})).To(Succeed())
+		})
+
+		It("should only send the records that are due", func() {
+			failingStatus = http.StatusOK
+			delivered, err := ob.ReplayDue(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(delivered).To(Equal(1))
+
+			records, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Message).To(Equal("scheduled"))
+		})
+
+		It("should reschedule records that fail again with an increasing backoff", func() {
+			ob.Backoff = time.Minute
+			records, _ := store.List()
+			id := records[1].ID
+
+			Expect(ob.Replay(context.Background(), id)).NotTo(Succeed())
+			first, err := store.Get(id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Attempts).To(Equal(1))
+
+			Expect(ob.Replay(context.Background(), id)).NotTo(Succeed())
+			second, err := store.Get(id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Attempts).To(Equal(2))
+			Expect(second.NextAttempt.Sub(first.NextAttempt)).To(BeNumerically(">", 50*time.Second))
+		})
+
+		It("should remove records that are sent successfully", func() {
+			failingStatus = http.StatusOK
+			records, _ := store.List()
+
+			Expect(ob.Replay(context.Background(), records[1].ID)).To(Succeed())
+			_, err := store.Get(records[1].ID)
+			Expect(err).To(MatchError(outbox.ErrNotFound))
+		})
+
+		It("should use the HTTP client of the router", func() {
+			var hosts []string
+			sr, err := router.New(nil)
+			Expect(err).NotTo(HaveOccurred())
+			sr.SetHTTPClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				hosts = append(hosts, req.URL.Host)
+				return httpmock.NewStringResponse(http.StatusOK, ""), nil
+			})})
+			ob = outbox.New(sr, store, nil)
+			records, _ := store.List()
+
+			Expect(ob.Replay(context.Background(), records[1].ID)).To(Succeed())
+			Expect(hosts).To(Equal([]string{"failing.example"}))
+		})
+	})
+})
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (rt roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
+
+var _ = Describe("the outbox store", func() {
+	var store *outbox.Store
+	BeforeEach(func() {
+		var err error
+		store, err = outbox.NewStore(filepath.Join(GinkgoT().TempDir(), "outbox"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should list the records in the order they were created", func() {
+		now := time.Now()
+		Expect(store.Add(&outbox.Record{Message: "second", Created: now})).To(Succeed())
+		Expect(store.Add(&outbox.Record{Message: "first", Created: now.Add(-time.Minute)})).To(Succeed())
+
+		records, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Message).To(Equal("first"))
+		Expect(records[1].Message).To(Equal("second"))
+	})
+
+	It("should ignore files that are not records", func() {
+		Expect(os.WriteFile(filepath.Join(store.Dir(), "README"), []byte("hello"), 0o600)).To(Succeed())
+		records, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+	})
+
+	It("should reject record IDs that are not plain file names", func() {
+		_, err := store.Get("../secrets")
+		Expect(err).To(HaveOccurred())
+		Expect(store.Remove("a/b")).NotTo(Succeed())
+	})
+
+	It("should return ErrNotFound when removing unknown records", func() {
+		Expect(store.Remove("1234-abcd")).To(MatchError(outbox.ErrNotFound))
+	})
+})
@@ -0,0 +1,201 @@
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+const recordExt = ".json"
+
+// ErrNotFound is returned when no record with the specified ID exists in the store
+var ErrNotFound = errors.New("record not found")
+
+// Record is a message that failed to be sent using a single service URL
+type Record struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Message     string    `json:"message"`
+	Params      t.Params  `json:"params,omitempty"`
+	Created     time.Time `json:"created"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"lastError,omitempty"`
+	NextAttempt time.Time `json:"nextAttempt"`
+}
+
+// Store persists records as JSON files in a directory
+type Store struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewStore returns a store using the specified directory, creating it if it does not exist
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory used by the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Add saves a new record to the store, assigning it an ID
+func (s *Store) Add(record *Record) error {
+	id, err := newID(record.Created)
+	if err != nil {
+		return err
+	}
+	record.ID = id
+	return s.Save(*record)
+}
+
+// Save writes the record to the store, replacing any existing record with the same ID
+func (s *Store) Save(record Record) error {
+	path, err := s.path(record.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Write to a temporary file first, so that a record is never left partially written
+	tmp, err := os.CreateTemp(s.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the record with the specified ID
+func (s *Store) Get(id string) (Record, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return Record{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return readRecord(path)
+}
+
+// List returns all the records in the store, oldest first
+func (s *Store) List() ([]Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox directory: %w", err)
+	}
+
+	records := make([]Record, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != recordExt {
+			continue
+		}
+		record, err := readRecord(filepath.Join(s.dir, name))
+		if errors.Is(err, ErrNotFound) {
+			// Removed after the directory was read
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Created.Before(records[j].Created)
+	})
+
+	return records, nil
+}
+
+// Remove deletes the record with the specified ID from the store
+func (s *Store) Remove(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %v", ErrNotFound, id)
+		}
+		return fmt.Errorf("failed to remove record: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid record ID %q", id)
+	}
+	return filepath.Join(s.dir, id+recordExt), nil
+}
+
+func readRecord(path string) (Record, error) {
+	record := Record{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return record, fmt.Errorf("%w: %v", ErrNotFound, strings.TrimSuffix(filepath.Base(path), recordExt))
+		}
+		return record, fmt.Errorf("failed to read record: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("failed to decode record %v: %w", filepath.Base(path), err)
+	}
+
+	return record, nil
+}
+
+// newID returns a unique ID that sorts by the creation time of the record
+func newID(created time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate record ID: %w", err)
+	}
+	return fmt.Sprintf("%d-%s", created.UnixNano(), hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,163 @@
+// Package alertmanager implements a receiver for the Alertmanager webhook, forwarding the alerts using a ServiceRouter
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+const (
+	// TitleTemplate is the ID of the template used for rendering the notification title
+	TitleTemplate = "title"
+	// MessageTemplate is the ID of the template used for rendering the notification message
+	MessageTemplate = "message"
+	// DefaultSeverityLabel is the alert label that is used for determining the message level
+	DefaultSeverityLabel = "severity"
+)
+
+// maxPayloadSize is the maximum size of a webhook payload accepted by the HTTP handler
+const maxPayloadSize = 4 << 20
+
+var defaultTemplates = map[string]*template.Template{
+	TitleTemplate: template.Must(template.New(TitleTemplate).Parse(
+		`[{{ .Status }}{{ if eq .Status "firing" }}:{{ len .Alerts.Firing }}{{ end }}] ` +
+			`{{ or .CommonLabels.alertname .GroupKey }}`,
+	)),
+	MessageTemplate: template.Must(template.New(MessageTemplate).Parse(
+		`{{ range .Alerts }}[{{ .Status }}] {{ or .Annotations.summary .Labels.alertname }}` +
+			`{{ with .Annotations.description }}: {{ . }}{{ end }}
+{{ end }}`,
+	)),
+}
+
+// DefaultLevels maps the commonly used severity label values to message levels
+var DefaultLevels = map[string]t.MessageLevel{
+	"critical":      t.Error,
+	"error":         t.Error,
+	"page":          t.Error,
+	"warning":       t.Warning,
+	"warn":          t.Warning,
+	"info":          t.Info,
+	"informational": t.Info,
+	"none":          t.Info,
+	"debug":         t.Debug,
+}
+
+// Receiver renders Alertmanager webhook payloads and forwards them using a ServiceRouter
+type Receiver struct {
+	router    *router.ServiceRouter
+	templater t.Templater
+	// SeverityLabel is the alert label used for determining the message level
+	SeverityLabel string
+	// Levels maps the (lower case) values of the severity label to message levels
+	Levels map[string]t.MessageLevel
+}
+
+// NewReceiver returns a receiver that forwards alerts using the router. The title and message templates are looked up
+// in the templater, using the default templates if templater is nil or does not contain them.
+func NewReceiver(sr *router.ServiceRouter, templater t.Templater) *Receiver {
+	return &Receiver{
+		router:        sr,
+		templater:     templater,
+		SeverityLabel: DefaultSeverityLabel,
+		Levels:        DefaultLevels,
+	}
+}
+
+// Receive renders the payload and sends it using the routers services
+func (receiver *Receiver) Receive(ctx context.Context, payload Payload) []error {
+	title, err := receiver.render(TitleTemplate, payload)
+	if err != nil {
+		return []error{err}
+	}
+
+	message, err := receiver.render(MessageTemplate, payload)
+	if err != nil {
+		return []error{err}
+	}
+
+	params := t.Params{}
+	params.SetTitle(title)
+
+	items := []t.MessageItem{{
+		Text:  message,
+		Level: receiver.Level(payload),
+	}}
+
+	return receiver.router.SendItemsContext(ctx, items, params)
+}
+
+// Level returns the message level of the payload. Resolved groups are always Info, while firing groups use the level
+// of the most severe firing alert.
+func (receiver *Receiver) Level(payload Payload) t.MessageLevel {
+	if payload.Status == StatusResolved {
+		return t.Info
+	}
+
+	level := t.Unknown
+	for _, alert := range payload.Alerts.Firing() {
+		severity := strings.ToLower(alert.Labels[receiver.SeverityLabel])
+		if alertLevel, found := receiver.Levels[severity]; found && alertLevel > level {
+			level = alertLevel
+		}
+	}
+	return level
+}
+
+func (receiver *Receiver) render(id string, payload Payload) (string, error) {
+	tpl := defaultTemplates[id]
+	if receiver.templater != nil {
+		if custom, hasCustom := receiver.templater.GetTemplate(id); hasCustom {
+			tpl = custom
+		}
+	}
+
+	builder := strings.Builder{}
+	if err := tpl.Execute(&builder, payload); err != nil {
+		return "", fmt.Errorf("failed to render %v template: %w", id, err)
+	}
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// ServeHTTP handles a webhook request from Alertmanager
+func (receiver *Receiver) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.Header().Set("Allow", http.MethodPost)
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload := Payload{}
+	if err := json.NewDecoder(http.MaxBytesReader(res, req.Body, maxPayloadSize)).Decode(&payload); err != nil {
+		http.Error(res, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if payload.Version != SupportedVersion {
+		http.Error(res, fmt.Sprintf("unsupported payload version %q", payload.Version), http.StatusBadRequest)
+		return
+	}
+
+	errs := receiver.Receive(req.Context(), payload)
+	if !receiver.router.Delivered(errs) {
+		var failed []string
+		for _, err := range errs {
+			if err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+		// Alertmanager will retry the notification if a 5xx status is returned, so it is only used if the alerts were
+		// not delivered according to the routers strategy
+		http.Error(res, strings.Join(failed, "\n"), http.StatusBadGateway)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
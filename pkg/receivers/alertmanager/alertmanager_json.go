@@ -0,0 +1,61 @@
+package alertmanager
+
+import "time"
+
+// SupportedVersion is the version of the Alertmanager webhook payload that is supported by the receiver
+const SupportedVersion = "4"
+
+const (
+	// StatusFiring is the status of alerts, and groups, that are currently active
+	StatusFiring = "firing"
+	// StatusResolved is the status of alerts, and groups, that are no longer active
+	StatusResolved = "resolved"
+)
+
+// Payload is the webhook payload sent by Alertmanager for a group of alerts
+type Payload struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            Alerts            `json:"alerts"`
+}
+
+// Alert is a single alert in the webhook payload
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Alerts is a list of alerts, with helpers for use in templates
+type Alerts []Alert
+
+// Firing returns the alerts that are firing
+func (alerts Alerts) Firing() Alerts {
+	return alerts.withStatus(StatusFiring)
+}
+
+// Resolved returns the alerts that have been resolved
+func (alerts Alerts) Resolved() Alerts {
+	return alerts.withStatus(StatusResolved)
+}
+
+func (alerts Alerts) withStatus(status string) Alerts {
+	filtered := Alerts{}
+	for _, alert := range alerts {
+		if alert.Status == status {
+			filtered = append(filtered, alert)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,13 @@
+package alertmanager_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAlertmanager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Alertmanager Receiver Suite")
+}
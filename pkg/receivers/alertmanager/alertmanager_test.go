@@ -0,0 +1,162 @@
+package alertmanager_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containrrr/shoutrrr/pkg/receivers/alertmanager"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+const firingPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"DiskFull\"}",
+  "status": "firing",
+  "receiver": "shoutrrr",
+  "groupLabels": {"alertname": "DiskFull"},
+  "commonLabels": {"alertname": "DiskFull"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "DiskFull", "instance": "db-1", "severity": "warning"},
+      "annotations": {"summary": "Disk on db-1 is almost full"},
+      "startsAt": "2023-01-01T10:00:00Z"
+    },
+    {
+      "status": "firing",
+      "labels": {"alertname": "DiskFull", "instance": "db-2", "severity": "critical"},
+      "annotations": {"summary": "Disk on db-2 is full", "description": "0 bytes left"},
+      "startsAt": "2023-01-01T10:00:00Z"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "DiskFull", "instance": "db-3", "severity": "critical"},
+      "annotations": {"summary": "Disk on db-3 is full"},
+      "startsAt": "2023-01-01T09:00:00Z",
+      "endsAt": "2023-01-01T09:30:00Z"
+    }
+  ]
+}`
+
+var _ = Describe("the alertmanager receiver", func() {
+	var output *bytes.Buffer
+	var receiver *alertmanager.Receiver
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		sr, err := router.New(log.New(output, "", 0), "logger://")
+		Expect(err).NotTo(HaveOccurred())
+		receiver = alertmanager.NewReceiver(sr, nil)
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, httptest.NewRequest("POST", "/alertmanager", strings.NewReader(body)))
+		return recorder
+	}
+
+	When("receiving a webhook payload", func() {
+		It("should forward the rendered alerts", func() {
+			res := post(firingPayload)
+			Expect(res.Code).To(Equal(http.StatusOK))
+			Expect(output.String()).To(Equal("[firing] Disk on db-1 is almost full\n" +
+				"[firing] Disk on db-2 is full: 0 bytes left\n" +
+				"[resolved] Disk on db-3 is full\n"))
+		})
+
+		It("should report the alerts as delivered if the routers strategy was satisfied", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
+				res.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			sr, err := router.New(log.New(output, "", 0), "generic+"+server.URL, "logger://")
+			Expect(err).NotTo(HaveOccurred())
+			sr.Strategy = router.Failover
+			receiver = alertmanager.NewReceiver(sr, nil)
+
+			Expect(post(firingPayload).Code).To(Equal(http.StatusOK))
+			Expect(output.String()).To(ContainSubstring("Disk on db-1 is almost full"))
+
+			sr.Strategy = router.Broadcast
+			Expect(post(firingPayload).Code).To(Equal(http.StatusBadGateway))
+		})
+
+		It("should reject payloads with an unsupported version", func() {
+			res := post(`{"version": "3", "status": "firing", "alerts": []}`)
+			Expect(res.Code).To(Equal(http.StatusBadRequest))
+			Expect(output.String()).To(BeEmpty())
+		})
+
+		It("should reject invalid payloads", func() {
+			Expect(post(`{"version": `).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should only accept POST requests", func() {
+			recorder := httptest.NewRecorder()
+			receiver.ServeHTTP(recorder, httptest.NewRequest("GET", "/alertmanager", nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	When("custom templates have been set", func() {
+		It("should render the message using them", func() {
+			templater := &standard.Templater{}
+			Expect(templater.SetTemplateString(alertmanager.MessageTemplate,
+				`{{ len .Alerts.Firing }} firing, {{ len .Alerts.Resolved }} resolved`)).To(Succeed())
+
+			sr, err := router.New(log.New(output, "", 0), "logger://")
+			Expect(err).NotTo(HaveOccurred())
+			receiver = alertmanager.NewReceiver(sr, templater)
+
+			Expect(post(firingPayload).Code).To(Equal(http.StatusOK))
+			Expect(output.String()).To(Equal("2 firing, 1 resolved\n"))
+		})
+
+		It("should return template errors", func() {
+			templater := &standard.Templater{}
+			Expect(templater.SetTemplateString(alertmanager.TitleTemplate, `{{ .Missing }}`)).To(Succeed())
+			receiver = alertmanager.NewReceiver(nil, templater)
+
+			errs := receiver.Receive(context.Background(), alertmanager.Payload{})
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(HaveOccurred())
+		})
+	})
+
+	When("determining the message level", func() {
+		It("should use the most severe firing alert", func() {
+			payload := alertmanager.Payload{Status: alertmanager.StatusFiring, Alerts: alertmanager.Alerts{
+				{Status: alertmanager.StatusFiring, Labels: map[string]string{"severity": "Warning"}},
+				{Status: alertmanager.StatusResolved, Labels: map[string]string{"severity": "critical"}},
+				{Status: alertmanager.StatusFiring, Labels: map[string]string{"severity": "info"}},
+			}}
+			Expect(receiver.Level(payload)).To(Equal(types.Warning))
+		})
+
+		It("should use Info for resolved groups", func() {
+			payload := alertmanager.Payload{Status: alertmanager.StatusResolved, Alerts: alertmanager.Alerts{
+				{Status: alertmanager.StatusResolved, Labels: map[string]string{"severity": "critical"}},
+			}}
+			Expect(receiver.Level(payload)).To(Equal(types.Info))
+		})
+
+		It("should use Unknown for unmapped severities", func() {
+			payload := alertmanager.Payload{Status: alertmanager.StatusFiring, Alerts: alertmanager.Alerts{
+				{Status: alertmanager.StatusFiring, Labels: map[string]string{"severity": "p5"}},
+			}}
+			Expect(receiver.Level(payload)).To(Equal(types.Unknown))
+		})
+	})
+})
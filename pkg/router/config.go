@@ -0,0 +1,346 @@
+package router
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/httpclient"
+)
+
+// DefaultTargetName is the target, or group, used by NewFromConfig when no target names are given
+const DefaultTargetName = "default"
+
+// ConfigFormat is the file format of a router configuration
+type ConfigFormat string
+
+const (
+	// YAMLFormat is used for configuration files with the .yaml or .yml extension
+	YAMLFormat ConfigFormat = "yaml"
+	// TOMLFormat is used for configuration files with the .toml extension
+	TOMLFormat ConfigFormat = "toml"
+)
+
+// Config defines named targets, and groups of targets, that can be used for creating a router
+type Config struct {
+	Targets map[string]TargetConfig `yaml:"targets" toml:"targets"`
+	Groups  map[string][]string     `yaml:"groups" toml:"groups"`
+	// HTTP configures the transport used by all services for their HTTP requests, if set
+	HTTP *httpclient.Options `yaml:"http" toml:"http"`
+	// Rules are the routing rules, selecting the targets that receive each message
+	Rules []RuleConfig `yaml:"rules" toml:"rules"`
+}
+
+// TargetConfig is a named target, consisting of one or more service URLs together with their default params and
+// templates
+type TargetConfig struct {
+	URL       string            `yaml:"url" toml:"url"`
+	URLs      []string          `yaml:"urls" toml:"urls"`
+	Params    map[string]string `yaml:"params" toml:"params"`
+	Templates map[string]string `yaml:"templates" toml:"templates"`
+}
+
+// RuleConfig is a routing rule, selecting the targets, and groups of targets, that receive the messages it matches.
+// The levels are the names of message levels, e.g. "warning", and the pattern is a regular expression.
+type RuleConfig struct {
+	Levels  []string `yaml:"levels" toml:"levels"`
+	Tags    []string `yaml:"tags" toml:"tags"`
+	Pattern string   `yaml:"pattern" toml:"pattern"`
+	Targets []string `yaml:"targets" toml:"targets"`
+}
+
+// ServiceURLs returns all the service URLs of the target
+func (target TargetConfig) ServiceURLs() []string {
+	if target.URL == "" {
+		return target.URLs
+	}
+	return append([]string{target.URL}, target.URLs...)
+}
+
+// LoadConfig reads the configuration file at path, using the file extension to determine the format
+func LoadConfig(path string) (*Config, error) {
+	var format ConfigFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = YAMLFormat
+	case ".toml":
+		format = TOMLFormat
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config, err := ParseConfig(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %v: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig parses the configuration data in the specified format
+func ParseConfig(data []byte, format ConfigFormat) (*Config, error) {
+	config := &Config{}
+
+	switch format {
+	case YAMLFormat:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case TOMLFormat:
+		decoder := toml.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(config); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (config *Config) validate() error {
+	for name, target := range config.Targets {
+		if len(target.ServiceURLs()) < 1 {
+			return fmt.Errorf("target %q has no service URLs", name)
+		}
+		if _, isGroup := config.Groups[name]; isGroup {
+			return fmt.Errorf("%q is defined both as a target and a group", name)
+		}
+	}
+	if _, err := config.Resolve(config.groupNames()...); err != nil {
+		return err
+	}
+	_, err := config.routingRules()
+	return err
+}
+
+// routingRules returns the rules of the config, with their groups resolved to target names
+func (config *Config) routingRules() ([]Rule, error) {
+	rules := make([]Rule, 0, len(config.Rules))
+	for i, ruleConfig := range config.Rules {
+		rule := Rule{Tags: ruleConfig.Tags}
+
+		for _, name := range ruleConfig.Levels {
+			level, err := t.ParseMessageLevel(name)
+			if err != nil {
+				return nil, fmt.Errorf("rule #%d: %w", i+1, err)
+			}
+			rule.Levels = append(rule.Levels, level)
+		}
+
+		if ruleConfig.Pattern != "" {
+			pattern, err := regexp.Compile(ruleConfig.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule #%d: invalid pattern: %w", i+1, err)
+			}
+			rule.Pattern = pattern
+		}
+
+		if len(ruleConfig.Targets) < 1 {
+			return nil, fmt.Errorf("rule #%d has no targets", i+1)
+		}
+		targets, err := config.Resolve(ruleConfig.Targets...)
+		if err != nil {
+			return nil, fmt.Errorf("rule #%d: %w", i+1, err)
+		}
+		rule.Services = targets
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (config *Config) groupNames() []string {
+	names := make([]string, 0, len(config.Groups))
+	for name := range config.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the target names that the specified target and group names refers to, without duplicates
+func (config *Config) Resolve(names ...string) ([]string, error) {
+	targets := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		var err error
+		if targets, err = config.resolve(name, targets, seen, nil); err != nil {
+			return nil, err
+		}
+	}
+	return targets, nil
+}
+
+func (config *Config) resolve(name string, targets []string, seen map[string]bool, path []string) ([]string, error) {
+	if _, isTarget := config.Targets[name]; isTarget {
+		if !seen[name] {
+			seen[name] = true
+			targets = append(targets, name)
+		}
+		return targets, nil
+	}
+
+	members, isGroup := config.Groups[name]
+	if !isGroup {
+		return nil, fmt.Errorf("unknown target %q", name)
+	}
+
+	for _, parent := range path {
+		if parent == name {
+			return nil, fmt.Errorf("group %q contains itself", name)
+		}
+	}
+
+	var err error
+	for _, member := range members {
+		if targets, err = config.resolve(member, targets, seen, append(path, name)); err != nil {
+			return nil, err
+		}
+	}
+	return targets, nil
+}
+
+// NewFromConfig creates a new service router using the services of the specified targets and groups in the config.
+// If no names are given, the target or group named "default" is used.
+func NewFromConfig(logger t.StdLogger, config *Config, names ...string) (*ServiceRouter, error) {
+	router, err := New(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := router.AddFromConfig(config, names...); err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
+// AddFromConfig adds the services of the specified targets and groups in the config, named after their target.
+// If no names are given, the target or group named "default" is used. If the config has HTTP options, the client
+// created from them is used by all the routers services, and its routing rules are added to the routers Rules.
+func (router *ServiceRouter) AddFromConfig(config *Config, names ...string) error {
+	services, rules, client, err := router.fromConfig(config, names)
+	if err != nil {
+		return err
+	}
+
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	if client != nil {
+		router.useHTTPClient(client)
+	}
+	current := router.services
+	router.setServices(append(current[:len(current):len(current)], services...))
+	router.Rules = append(router.Rules, rules...)
+	return nil
+}
+
+// ReplaceFromConfig replaces all the routers services and Rules with the ones of the specified targets and groups in
+// the config, as a single change. If no names are given, the target or group named "default" is used. If any of the
+// services fails to initialize, an error is returned and the current services are kept. The HTTP client is replaced as
+// well, so if the config has no HTTP options, the services use the client set using SetHTTPClient again, if any.
+func (router *ServiceRouter) ReplaceFromConfig(config *Config, names ...string) error {
+	services, rules, client, err := router.fromConfig(config, names)
+	if err != nil {
+		return err
+	}
+
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	if client == nil {
+		client = router.baseClient
+	}
+	router.httpClient = client
+	for _, rs := range services {
+		setHTTPClient(rs.Service, client)
+	}
+	router.setServices(services)
+	router.Rules = rules
+	return nil
+}
+
+// fromConfig returns the services of the targets and groups in the config, together with its routing rules and the
+// HTTP client created from its options, if any. The client is already used by the returned services.
+func (router *ServiceRouter) fromConfig(config *Config, names []string) ([]routedService, []Rule, *http.Client, error) {
+	if len(names) < 1 {
+		names = []string{DefaultTargetName}
+	}
+
+	targetNames, err := config.Resolve(names...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var client *http.Client
+	if config.HTTP != nil {
+		if client, err = httpclient.New(*config.HTTP); err != nil {
+			return nil, nil, nil, fmt.Errorf("error creating HTTP client: %w", err)
+		}
+	}
+
+	rules, err := config.routingRules()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var services []routedService
+	for _, name := range targetNames {
+		targetServices, err := router.targetServices(name, config.Targets[name])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error initializing services for target %q: %s", name, err)
+		}
+		services = append(services, targetServices...)
+	}
+
+	if client != nil {
+		for _, rs := range services {
+			setHTTPClient(rs.Service, client)
+		}
+	}
+
+	return services, rules, client, nil
+}
+
+// targetServices returns the services of the target, using its name and its default params and templates
+func (router *ServiceRouter) targetServices(name string, target TargetConfig) ([]routedService, error) {
+	var services []routedService
+	for _, serviceURL := range target.ServiceURLs() {
+		rs, err := router.initRoutedService(serviceURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, body := range target.Templates {
+			if err := rs.SetTemplateString(id, body); err != nil {
+				return nil, fmt.Errorf("failed to parse template %q: %w", id, err)
+			}
+		}
+
+		rs.name = name
+		rs.params = target.Params
+		services = append(services, rs)
+	}
+	return services, nil
+}
@@ -0,0 +1,214 @@
+package router
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/httpclient"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testConfigYAML = `
+targets:
+  logs:
+    url: logger://
+    params:
+      title: from config
+    templates:
+      message: "[{{ .title }}] {{ .message }}"
+  more-logs:
+    urls:
+      - logger://
+      - logger://?retries=2
+groups:
+  ops: [logs, more-logs]
+  everyone: [ops, logs]
+`
+
+const testConfigTOML = `
+[targets.logs]
+url = "logger://"
+
+[targets.logs.params]
+title = "from config"
+
+[groups]
+ops = ["logs"]
+
+[http]
+proxy = "http://proxy.example.com:3128"
+insecureSkipVerify = true
+`
+
+var _ = Describe("the router config", func() {
+	When("parsing a config file", func() {
+		It("should parse YAML files", func() {
+			config, err := ParseConfig([]byte(testConfigYAML), YAMLFormat)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Targets).To(HaveLen(2))
+			Expect(config.Targets["logs"].Params).To(HaveKeyWithValue("title", "from config"))
+			Expect(config.Targets["more-logs"].ServiceURLs()).To(HaveLen(2))
+			Expect(config.Groups["ops"]).To(Equal([]string{"logs", "more-logs"}))
+		})
+
+		It("should parse TOML files", func() {
+			config, err := ParseConfig([]byte(testConfigTOML), TOMLFormat)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Targets["logs"].ServiceURLs()).To(Equal([]string{"logger://"}))
+			Expect(config.Targets["logs"].Params).To(HaveKeyWithValue("title", "from config"))
+			Expect(config.Groups["ops"]).To(Equal([]string{"logs"}))
+			Expect(config.HTTP.ProxyURL).To(Equal("http://proxy.example.com:3128"))
+			Expect(config.HTTP.InsecureSkipVerify).To(BeTrue())
+		})
+
+		It("should use the file extension to determine the format", func() {
+			dir := GinkgoT().TempDir()
+			yamlFile := filepath.Join(dir, "shoutrrr.yml")
+			Expect(os.WriteFile(yamlFile, []byte(testConfigYAML), 0o600)).To(Succeed())
+			_, err := LoadConfig(yamlFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			jsonFile := filepath.Join(dir, "shoutrrr.json")
+			Expect(os.WriteFile(jsonFile, []byte("{}"), 0o600)).To(Succeed())
+			_, err = LoadConfig(jsonFile)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for unknown keys", func() {
+			_, err := ParseConfig([]byte("targets:\n  logs:\n    uri: logger://\n"), YAMLFormat)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for targets without URLs", func() {
+			_, err := ParseConfig([]byte("targets:\n  logs:\n    params: {}\n"), YAMLFormat)
+			Expect(err).To(MatchError(ContainSubstring("no service URLs")))
+		})
+
+		It("should return an error for groups with unknown members", func() {
+			_, err := ParseConfig([]byte("groups:\n  ops: [missing]\n"), YAMLFormat)
+			Expect(err).To(MatchError(ContainSubstring(`unknown target "missing"`)))
+		})
+
+		It("should return an error for groups that contain themselves", func() {
+			_, err := ParseConfig([]byte("groups:\n  a: [b]\n  b: [a]\n"), YAMLFormat)
+			Expect(err).To(MatchError(ContainSubstring("contains itself")))
+		})
+	})
+
+	When("resolving target names", func() {
+		It("should expand groups without duplicating targets", func() {
+			config, err := ParseConfig([]byte(testConfigYAML), YAMLFormat)
+			Expect(err).NotTo(HaveOccurred())
+
+			targets, err := config.Resolve("everyone", "more-logs")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal([]string{"logs", "more-logs"}))
+		})
+	})
+
+	When("creating a router from a config", func() {
+		var config *Config
+		BeforeEach(func() {
+			var err error
+			config, err = ParseConfig([]byte(testConfigYAML), YAMLFormat)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should add the services of all the specified targets", func() {
+			sr, err := NewFromConfig(nil, config, "ops")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sr.ServiceURLs()).To(Equal([]string{"logger://", "logger://", "logger://?retries=2"}))
+			Expect(*sr.services[2].options.retries).To(Equal(2))
+		})
+
+		It("should use the default params and templates of the target", func() {
+			output := &bytes.Buffer{}
+			sr, err := NewFromConfig(log.New(output, "", 0), config, "logs")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sr.Send("hello", nil)).To(ConsistOf(BeNil()))
+			Expect(output.String()).To(Equal("[from config] hello\n"))
+		})
+
+		It("should let the send params override the default params", func() {
+			output := &bytes.Buffer{}
+			sr, err := NewFromConfig(log.New(output, "", 0), config, "logs")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sr.Send("hello", &t.Params{"title": "override"})).To(ConsistOf(BeNil()))
+			Expect(output.String()).To(Equal("[override] hello\n"))
+		})
+
+		It("should use the default target if no names are given", func() {
+			_, err := NewFromConfig(nil, config)
+			Expect(err).To(MatchError(ContainSubstring(`unknown target "default"`)))
+
+			config.Groups[DefaultTargetName] = []string{"logs"}
+			sr, err := NewFromConfig(nil, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sr.ServiceURLs()).To(HaveLen(1))
+		})
+
+		It("should use a HTTP client with the transport options of the config for all services", func() {
+			config.HTTP = &httpclient.Options{InsecureSkipVerify: true}
+			sr, err := New(nil, "strategy-test://before")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sr.AddFromConfig(config, "logs")).To(Succeed())
+			Expect(sr.AddService("strategy-test://after")).To(Succeed())
+
+			for _, i := range []int{0, 2} {
+				client := sr.services[i].Service.(*strategyTestService).GetHTTPClient()
+				Expect(client).NotTo(BeIdenticalTo(http.DefaultClient))
+				Expect(client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+			}
+		})
+
+		It("should restore the default HTTP client when reloading a config without transport options", func() {
+			config.Targets["chat"] = TargetConfig{URL: "strategy-test://chat"}
+			config.HTTP = &httpclient.Options{InsecureSkipVerify: true}
+			sr, err := NewFromConfig(nil, config, "chat")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sr.services[0].Service.(*strategyTestService).GetHTTPClient()).NotTo(BeIdenticalTo(http.DefaultClient))
+
+			config.HTTP = nil
+			Expect(sr.ReplaceFromConfig(config, "chat")).To(Succeed())
+			Expect(sr.services[0].Service.(*strategyTestService).GetHTTPClient()).To(BeIdenticalTo(http.DefaultClient))
+
+			Expect(sr.AddService("strategy-test://after")).To(Succeed())
+			Expect(sr.services[1].Service.(*strategyTestService).GetHTTPClient()).To(BeIdenticalTo(http.DefaultClient))
+		})
+
+		It("should restore the client set using SetHTTPClient when reloading a config without transport options", func() {
+			base := &http.Client{}
+			sr, err := New(nil)
+			Expect(err).NotTo(HaveOccurred())
+			sr.SetHTTPClient(base)
+
+			config.Targets["chat"] = TargetConfig{URL: "strategy-test://chat"}
+			config.HTTP = &httpclient.Options{InsecureSkipVerify: true}
+			Expect(sr.ReplaceFromConfig(config, "chat")).To(Succeed())
+			client := sr.services[0].Service.(*strategyTestService).GetHTTPClient()
+			Expect(client).NotTo(BeIdenticalTo(base))
+			Expect(client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+
+			config.HTTP = nil
+			Expect(sr.ReplaceFromConfig(config, "chat")).To(Succeed())
+			Expect(sr.services[0].Service.(*strategyTestService).GetHTTPClient()).To(BeIdenticalTo(base))
+
+			Expect(sr.AddService("strategy-test://after")).To(Succeed())
+			Expect(sr.services[1].Service.(*strategyTestService).GetHTTPClient()).To(BeIdenticalTo(base))
+		})
+
+		It("should return an error for invalid transport options", func() {
+			config.HTTP = &httpclient.Options{ProxyURL: "://proxy"}
+			_, err := NewFromConfig(nil, config, "logs")
+			Expect(err).To(MatchError(ContainSubstring("error creating HTTP client")))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDelay is the time to wait for further changes to the config file before reloading it, since editors
+// often write files in multiple steps
+const configReloadDelay = 100 * time.Millisecond
+
+// WatchConfig reloads the configuration file at path whenever it changes, replacing the routers services and Rules
+// using ReplaceFromConfig with the specified targets and groups, until ctx is done. If the changed file cannot be
+// loaded, the error is logged and the current services are kept.
+func (router *ServiceRouter) WatchConfig(ctx context.Context, path string, names ...string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+	defer watcher.Close()
+
+	// The directory is watched rather than the file, since editors often replace the file rather than writing to it
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	reload := time.NewTimer(configReloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				reload.Reset(configReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			router.log("Error watching config file:", err)
+		case <-reload.C:
+			router.reloadConfig(path, names)
+		}
+	}
+}
+
+// reloadConfig replaces the routers services and rules with the ones in the configuration file at path
+func (router *ServiceRouter) reloadConfig(path string, names []string) {
+	config, err := LoadConfig(path)
+	if err == nil {
+		err = router.ReplaceFromConfig(config, names...)
+	}
+
+	if err != nil {
+		router.log("Failed to reload config, keeping the current services:", err)
+		return
+	}
+	router.log("Reloaded config from", path)
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// contextAdapter wraps services that does not implement the ContextSender interface, allowing them to be used
+// with a context. Note that the underlying Send call cannot be aborted, it will only be abandoned when ctx is done.
+type contextAdapter struct {
+	t.Service
+}
+
+// SendContext calls the wrapped services Send method, returning early with the context error if ctx is done
+func (ca contextAdapter) SendContext(ctx context.Context, message string, params *t.Params) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	result := make(chan error, 1)
+	go func() { result <- ca.Service.Send(message, params) }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AsContextSender returns the service as a ContextSender, wrapping it in an adapter if it does not implement it
+func AsContextSender(service t.Service) t.ContextSender {
+	if sender, ok := service.(t.ContextSender); ok {
+		return sender
+	}
+	return contextAdapter{service}
+}
+
+// richAdapter allows services to be used as a RichContextSender. Services that implements RichSender, but not
+// RichContextSender, are called the same way as in contextAdapter. Services that does not implement RichSender at all
+// are sent the text of the items as a plain message instead, unless the items have attachments.
+type richAdapter struct {
+	t.Service
+}
+
+// SendItemsContext sends the items using the wrapped services SendItems method if it has one, otherwise falling back
+// to sending them as a plain message
+func (ra richAdapter) SendItemsContext(ctx context.Context, items []t.MessageItem, params t.Params) error {
+	richSender, isRich := ra.Service.(t.RichSender)
+	if !isRich {
+		if len(t.ItemsAttachments(items)) > 0 {
+			return fmt.Errorf("failed to send using %v: %w", serviceName(ra.Service), t.ErrAttachmentsUnsupported)
+		}
+		message := strings.TrimSuffix(t.ItemsToPlain(items), "\n")
+		return AsContextSender(ra.Service).SendContext(ctx, message, &params)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	result := make(chan error, 1)
+	go func() { result <- richSender.SendItems(items, params) }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AsRichContextSender returns the service as a RichContextSender, wrapping it in an adapter if it does not implement
+// it. Services without any support for rich messages will be sent the items as a plain message.
+func AsRichContextSender(service t.Service) t.RichContextSender {
+	if sender, ok := service.(t.RichContextSender); ok {
+		return sender
+	}
+	return richAdapter{service}
+}
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// blockingService is a legacy service that does not implement ContextSender and blocks until released
+type blockingService struct {
+	standard.Standard
+	release chan struct{}
+}
+
+func (s *blockingService) Initialize(_ *url.URL, logger t.StdLogger) error {
+	s.SetLogger(logger)
+	return nil
+}
+
+func (s *blockingService) Send(_ string, _ *t.Params) error {
+	<-s.release
+	return nil
+}
+
+// richService records the items sent using its SendItems method
+type richService struct {
+	standard.Standard
+	items   []t.MessageItem
+	message string
+}
+
+func (s *richService) Initialize(_ *url.URL, logger t.StdLogger) error {
+	s.SetLogger(logger)
+	return nil
+}
+
+func (s *richService) Send(message string, _ *t.Params) error {
+	s.message = message
+	return nil
+}
+
+func (s *richService) SendItems(items []t.MessageItem, _ t.Params) error {
+	s.items = items
+	return nil
+}
+
+// plainService records the messages sent using its Send method
+type plainService struct {
+	richService
+}
+
+// SendItems shadows the method of richService, making plainService not implement RichSender
+func (s *plainService) SendItems() {}
+
+var _ = Describe("sending with a context", func() {
+	var service *blockingService
+
+	BeforeEach(func() {
+		service = &blockingService{release: make(chan struct{})}
+	})
+	AfterEach(func() {
+		close(service.release)
+	})
+
+	When("the service does not implement ContextSender", func() {
+		It("should be wrapped in an adapter", func() {
+			sender := AsContextSender(service)
+			Expect(sender).To(BeAssignableToTypeOf(contextAdapter{}))
+		})
+		It("should return the context error when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				cancel()
+			}()
+			err := AsContextSender(service).SendContext(ctx, "message", nil)
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+
+	When("the router context is cancelled", func() {
+		It("should return an error for each pending service", func() {
+			router := ServiceRouter{services: []routedService{{Service: service}}, Timeout: time.Minute}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			errs := router.SendContext(ctx, "message", nil)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(context.Canceled))
+		})
+	})
+
+	When("the router timeout is reached", func() {
+		It("should return a timed out error", func() {
+			router := ServiceRouter{services: []routedService{{Service: service}}, Timeout: 10 * time.Millisecond}
+			errs := router.Send("message", nil)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(ContainSubstring("timed out")))
+		})
+	})
+
+	When("sending message items", func() {
+		items := []t.MessageItem{{Text: "first"}, {Text: "second", Level: t.Warning}}
+
+		It("should dispatch the items to services that implement RichSender", func() {
+			rich := &richService{}
+			router := ServiceRouter{services: []routedService{{Service: rich}}}
+			Expect(router.SendItems(items, nil)).To(Equal([]error{nil}))
+			Expect(rich.items).To(Equal(items))
+			Expect(rich.message).To(BeEmpty())
+		})
+
+		It("should send the text of the items to services that does not implement RichSender", func() {
+			plain := &plainService{}
+			router := ServiceRouter{services: []routedService{{Service: plain}}}
+			Expect(router.SendItems(items, nil)).To(Equal([]error{nil}))
+			Expect(plain.items).To(BeNil())
+			Expect(plain.message).To(Equal("first\nsecond"))
+		})
+
+		It("should report that attachments are unsupported by services that does not implement RichSender", func() {
+			plain := &plainService{}
+			router := ServiceRouter{services: []routedService{{Service: plain}}}
+			withAttachment := []t.MessageItem{{Text: "log", Attachments: []t.Attachment{t.NewAttachment("a.log", nil)}}}
+			errs := router.SendItems(withAttachment, nil)
+			Expect(errs).To(HaveLen(1))
+			Expect(errors.Is(errs[0], t.ErrAttachmentsUnsupported)).To(BeTrue())
+			Expect(plain.message).To(BeEmpty())
+		})
+	})
+})
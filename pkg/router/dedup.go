@@ -0,0 +1,179 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// DedupPolicy describes how repeated sends of the same message are suppressed
+type DedupPolicy struct {
+	// Window is the duration, starting at the first send of a message, during which repeats of it are suppressed.
+	// If the message is not delivered according to the routers Strategy, the window is closed, so that it can be sent
+	// again. Zero disables the deduplication.
+	Window time.Duration
+	// Summary enables sending a message with the number of suppressed repeats when the window closes, if any
+	Summary bool
+}
+
+// deduplicator keeps track of the messages that have been sent within their suppression window
+type deduplicator struct {
+	lock    sync.Mutex
+	windows map[string]*dedupWindow
+}
+
+// dedupWindow is the suppression window of a single message
+type dedupWindow struct {
+	suppressed int
+	timer      *time.Timer
+}
+
+// dedupKey returns a hash of the notification and params, which includes the title
+func dedupKey(content notification, params t.Params) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	// Length prefixes prevents different combinations of values from producing the same input
+	identity := content.identity()
+	_, _ = fmt.Fprintf(hash, "%t%d:%s", content.rich, len(identity), identity)
+	for _, key := range keys {
+		value := params[key]
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:%s", len(key), key, len(value), value)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// suppress returns whether the notification is a repeat within its suppression window. If it is not, a new window is
+// started, calling onClose with the number of suppressed repeats when it closes, and a function that closes the window
+// early without calling onClose is returned.
+func (d *deduplicator) suppress(content notification, params t.Params, window time.Duration, onClose func(suppressed int)) (bool, func()) {
+	key := dedupKey(content, params)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if current, found := d.windows[key]; found {
+		current.suppressed++
+		return true, nil
+	}
+
+	if d.windows == nil {
+		d.windows = make(map[string]*dedupWindow)
+	}
+	current := &dedupWindow{}
+	d.windows[key] = current
+
+	current.timer = time.AfterFunc(window, func() {
+		if !d.close(key, current) {
+			return
+		}
+		onClose(current.suppressed)
+	})
+
+	return false, func() {
+		if d.close(key, current) {
+			current.timer.Stop()
+		}
+	}
+}
+
+// close removes the window of the key, returning whether it was still open
+func (d *deduplicator) close(key string, window *dedupWindow) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.windows[key] != window {
+		return false
+	}
+	delete(d.windows, key)
+	return true
+}
+
+// suppressDuplicate returns whether the notification should be suppressed according to the routers DedupPolicy. If it
+// is not, and a suppression window was started for it, a function that closes the window is returned as well.
+func (router *ServiceRouter) suppressDuplicate(content notification, params t.Params) (bool, func()) {
+	policy := router.Dedup
+	if policy.Window <= 0 {
+		return false, nil
+	}
+
+	router.dedupOnce.Do(func() {
+		router.dedup = &deduplicator{}
+	})
+
+	suppressed, closeWindow := router.dedup.suppress(content, params, policy.Window, func(suppressed int) {
+		if suppressed < 1 || !policy.Summary {
+			return
+		}
+		router.sendSummary(content.text(), params, suppressed, policy.Window)
+	})
+
+	if suppressed {
+		router.log("Suppressed duplicate message:", util.Ellipsis(content.text(), 100))
+	}
+	return suppressed, closeWindow
+}
+
+// deliverOnce delivers the notification like deliver, but calls closeWindow before passing on the last result if the
+// notification was not delivered according to the routers Strategy, so that it can be sent again
+func (router *ServiceRouter) deliverOnce(ctx context.Context, send *pendingSend, content notification, params t.Params, closeWindow func()) {
+	results := send.results
+	checked := *send
+	checked.results = make(chan serviceResult, len(send.services))
+	router.deliver(ctx, &checked, content, params)
+
+	go func() {
+		sendResults := make([]SendResult, len(send.services))
+		for i := range send.services {
+			result := <-checked.results
+			sendResults[result.index] = result.SendResult
+			if i == len(send.services)-1 && !router.DeliveredResults(sendResults) {
+				router.log("Message was not delivered, closing its suppression window")
+				closeWindow()
+			}
+			results <- result
+		}
+	}()
+}
+
+// sendSummary sends a message with the number of repeats of the message that were suppressed during the window
+func (router *ServiceRouter) sendSummary(message string, params t.Params, suppressed int, window time.Duration) {
+	noun := "duplicates"
+	if suppressed == 1 {
+		noun = "duplicate"
+	}
+	summary := fmt.Sprintf("Suppressed %d %s in last %v of: %v",
+		suppressed, noun, formatWindow(window), util.Ellipsis(message, 100))
+
+	send := router.newSend()
+	router.deliver(context.Background(), send, plainNotification(summary), params)
+	for range send.services {
+		if result := <-send.results; result.Err != nil {
+			router.log("Failed to send dedup summary:", result.Err)
+		}
+	}
+}
+
+// formatWindow returns the duration without any trailing zero units, e.g. 10m instead of 10m0s
+func formatWindow(window time.Duration) string {
+	formatted := window.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
@@ -0,0 +1,106 @@
+package router
+
+import (
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("the message deduplication", func() {
+	BeforeEach(resetSends)
+
+	It("should not suppress anything by default", func() {
+		router := newStrategyRouter(Broadcast, "a")
+		router.Send("message", nil)
+		router.Send("message", nil)
+		Expect(sentMessages()).To(HaveLen(2))
+	})
+
+	When("a window is set", func() {
+		It("should suppress repeats of the same message within the window", func() {
+			router := newStrategyRouter(Broadcast, "a", "b")
+			router.Dedup = DedupPolicy{Window: time.Minute}
+
+			Expect(router.Send("message", nil)).To(Equal([]error{nil, nil}))
+			Expect(router.Send("message", nil)).To(Equal([]error{nil, nil}))
+			Expect(sentMessages()).To(Equal([]string{"message", "message"}))
+		})
+
+		It("should not suppress messages with a different title or params", func() {
+			router := newStrategyRouter(Broadcast, "a")
+			router.Dedup = DedupPolicy{Window: time.Minute}
+
+			router.Send("message", nil)
+			router.Send("message", &t.Params{"title": "title"})
+			router.Send("message", &t.Params{"title": "other"})
+			router.Send("other message", &t.Params{"title": "title"})
+			Expect(sentMessages()).To(HaveLen(4))
+		})
+
+		It("should not suppress repeats of a message that was not delivered", func() {
+			router := newStrategyRouter(Broadcast, "fail", "a")
+			router.Dedup = DedupPolicy{Window: time.Minute}
+
+			Expect(router.Send("message", nil)[0]).To(HaveOccurred())
+			Expect(router.Send("message", nil)[0]).To(HaveOccurred())
+			Expect(sentHosts()).To(HaveLen(4))
+		})
+
+		It("should suppress repeats of a message that was delivered according to the strategy", func() {
+			router := newStrategyRouter(Failover, "fail", "a")
+			router.Dedup = DedupPolicy{Window: time.Minute}
+
+			Expect(router.Send("message", nil)[0]).To(HaveOccurred())
+			Expect(router.Send("message", nil)).To(Equal([]error{nil, nil}))
+			Expect(sentHosts()).To(Equal([]string{"fail", "a"}))
+		})
+
+		It("should send the message again after the window has closed", func() {
+			router := newStrategyRouter(Broadcast, "a")
+			router.Dedup = DedupPolicy{Window: 50 * time.Millisecond}
+
+			router.Send("message", nil)
+			router.Send("message", nil)
+			Expect(sentMessages()).To(HaveLen(1))
+
+			Eventually(func() []string {
+				router.Send("message", nil)
+				return sentMessages()
+			}).WithTimeout(time.Second).Should(HaveLen(2))
+		})
+	})
+
+	When("summaries are enabled", func() {
+		It("should send the number of suppressed repeats when the window closes", func() {
+			router := newStrategyRouter(Broadcast, "a")
+			router.Dedup = DedupPolicy{Window: 50 * time.Millisecond, Summary: true}
+
+			for i := 0; i < 4; i++ {
+				router.Send("disk full", nil)
+			}
+
+			Eventually(sentMessages).WithTimeout(time.Second).Should(Equal([]string{
+				"disk full",
+				"Suppressed 3 duplicates in last 50ms of: disk full",
+			}))
+		})
+
+		It("should not send a summary if nothing was suppressed", func() {
+			router := newStrategyRouter(Broadcast, "a")
+			router.Dedup = DedupPolicy{Window: 10 * time.Millisecond, Summary: true}
+
+			router.Send("disk full", nil)
+			Consistently(sentMessages, 100*time.Millisecond).Should(HaveLen(1))
+		})
+	})
+
+	It("should format the window without trailing zero units", func() {
+		Expect(formatWindow(10 * time.Minute)).To(Equal("10m"))
+		Expect(formatWindow(2 * time.Hour)).To(Equal("2h"))
+		Expect(formatWindow(90 * time.Minute)).To(Equal("1h30m"))
+		Expect(formatWindow(30 * time.Second)).To(Equal("30s"))
+	})
+})
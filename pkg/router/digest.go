@@ -0,0 +1,119 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// digestSearchRunes is the number of runes searched for a whitespace to split at, when a queued message is too long
+// to fit in a single digest
+const digestSearchRunes = 100
+
+// DigestPolicy describes when the queued messages are flushed by RunDigest
+type DigestPolicy struct {
+	// Interval is the time between flushes of the queued messages. Zero disables flushing on an interval.
+	Interval time.Duration
+	// MaxItems flushes the queued messages as soon as there are this many. Zero disables flushing on the item count.
+	MaxItems int
+	// Params are used for sending every digest
+	Params *t.Params
+}
+
+// RunDigest flushes the queued messages in the background according to the policy, until ctx is done. Any messages
+// still queued when ctx is done are flushed before returning.
+func (router *ServiceRouter) RunDigest(ctx context.Context, policy DigestPolicy) error {
+	full := make(chan struct{}, 1)
+	router.queueLock.Lock()
+	router.queueFull = full
+	router.queueMaxItems = policy.MaxItems
+	router.queueLock.Unlock()
+
+	defer func() {
+		router.queueLock.Lock()
+		router.queueFull = nil
+		router.queueLock.Unlock()
+		router.FlushContext(context.Background(), policy.Params)
+	}()
+
+	var tick <-chan time.Time
+	if policy.Interval > 0 {
+		ticker := time.NewTicker(policy.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		case <-full:
+		}
+		router.FlushContext(ctx, policy.Params)
+	}
+}
+
+// digestNotification returns a plain notification of the queued messages, which is split into multiple messages when
+// it exceeds the MessageLimit of a service
+func digestNotification(queue []string) notification {
+	return notification{message: strings.Join(queue, "\n"), digest: true}
+}
+
+// parts returns the notifications to send using the service. Digests are split into messages that each fit in a
+// single chunk of the services MessageLimit, while other notifications are sent as is.
+func (n notification) parts(service t.Service) []notification {
+	limited, isLimited := service.(t.MessageLimitService)
+	if !n.digest || !isLimited || limited.MessageLimit().ChunkSize < 1 {
+		return []notification{n}
+	}
+
+	messages := splitDigest(n.message, limited.MessageLimit().ChunkSize)
+	parts := make([]notification, len(messages))
+	for i, message := range messages {
+		parts[i] = notification{message: message, markdown: n.markdown}
+	}
+	return parts
+}
+
+// splitDigest joins the lines of the text into as few messages as possible that are at most maxLength runes. Lines
+// that are too long to fit in a message by themselves are partitioned using util.PartitionMessage.
+func splitDigest(text string, maxLength int) []string {
+	var messages []string
+	current := []string{}
+	currentLength := 0
+
+	add := func(line string, length int) {
+		if len(current) > 0 && currentLength+1+length > maxLength {
+			messages = append(messages, strings.Join(current, "\n"))
+			current, currentLength = []string{}, 0
+		}
+		if len(current) > 0 {
+			currentLength++
+		}
+		current = append(current, line)
+		currentLength += length
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		length := len([]rune(line))
+		if length <= maxLength {
+			add(line, length)
+			continue
+		}
+
+		limits := t.MessageLimit{ChunkSize: maxLength, TotalChunkSize: length, ChunkCount: length + 1}
+		items, _ := util.PartitionMessage(line, limits, util.Min(digestSearchRunes, maxLength-1))
+		for _, item := range items {
+			add(item.Text, len([]rune(item.Text)))
+		}
+	}
+
+	if len(current) > 0 {
+		messages = append(messages, strings.Join(current, "\n"))
+	}
+	return messages
+}
@@ -0,0 +1,147 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// digestTestService records the messages it is sent, using the host of its URL as the chunk size of its MessageLimit
+type digestTestService struct {
+	standard.Standard
+	chunkSize int
+	lock      sync.Mutex
+	messages  []string
+}
+
+func (service *digestTestService) Initialize(serviceURL *url.URL, _ t.StdLogger) error {
+	service.chunkSize, _ = strconv.Atoi(serviceURL.Host)
+	return nil
+}
+
+func (service *digestTestService) Send(message string, _ *t.Params) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	service.messages = append(service.messages, message)
+	return nil
+}
+
+func (service *digestTestService) MessageLimit() t.MessageLimit {
+	return t.MessageLimit{ChunkSize: service.chunkSize, TotalChunkSize: service.chunkSize, ChunkCount: 1}
+}
+
+func (service *digestTestService) sent() []string {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	return append([]string{}, service.messages...)
+}
+
+// newDigestRouter returns a router using a digest test service for each of the chunk sizes
+func newDigestRouter(chunkSizes ...int) (*ServiceRouter, []*digestTestService) {
+	router, err := New(nil)
+	Expect(err).NotTo(HaveOccurred())
+	services := make([]*digestTestService, len(chunkSizes))
+	for i, chunkSize := range chunkSizes {
+		Expect(router.AddService(fmt.Sprintf("digest-test://%d", chunkSize))).To(Succeed())
+		services[i] = router.services[i].Service.(*digestTestService)
+	}
+	return router, services
+}
+
+var _ = Describe("the message digests", func() {
+	It("should join the queued messages into as few messages as fits the limit of each service", func() {
+		router, services := newDigestRouter(0, 12)
+		router.Enqueue("first")
+		router.Enqueue("second")
+		router.Enqueue("third")
+		router.Flush(nil)
+
+		Expect(services[0].sent()).To(Equal([]string{"first\nsecond\nthird"}))
+		Expect(services[1].sent()).To(Equal([]string{"first\nsecond", "third"}))
+		Expect(router.queue).To(BeEmpty())
+	})
+
+	It("should split queued messages that are longer than the limit", func() {
+		Expect(splitDigest("short\nthis message is too long\nend", 10)).To(Equal([]string{
+			"short\nthis",
+			"message is",
+			"too long",
+			"end",
+		}))
+		Expect(splitDigest("åäöåäöåäö", 4)).To(Equal([]string{"åäöå", "äöåä", "ö"}))
+	})
+
+	It("should not send anything when the queue is empty", func() {
+		router, services := newDigestRouter(0)
+		router.Flush(nil)
+		Expect(services[0].sent()).To(BeEmpty())
+	})
+
+	It("should be safe to enqueue messages concurrently", func() {
+		router, services := newDigestRouter(0)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				router.Enqueue("message %d", i)
+			}(i)
+		}
+		wg.Wait()
+		router.Flush(nil)
+
+		Expect(services[0].sent()).To(HaveLen(1))
+		Expect(strings.Split(services[0].sent()[0], "\n")).To(HaveLen(50))
+	})
+
+	When("running in the background", func() {
+		It("should flush when the queue reaches the max items", func() {
+			router, services := newDigestRouter(0)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- router.RunDigest(ctx, DigestPolicy{MaxItems: 2}) }()
+
+			Eventually(func() bool {
+				router.queueLock.Lock()
+				defer router.queueLock.Unlock()
+				return router.queueFull != nil
+			}).Should(BeTrue())
+
+			router.Enqueue("a")
+			Consistently(services[0].sent, "50ms").Should(BeEmpty())
+			router.Enqueue("b")
+			Eventually(services[0].sent).Should(Equal([]string{"a\nb"}))
+
+			router.Enqueue("c")
+			cancel()
+			Expect(<-done).To(MatchError(context.Canceled))
+			Expect(services[0].sent()).To(Equal([]string{"a\nb", "c"}))
+		})
+
+		It("should flush on the interval", func() {
+			router, services := newDigestRouter(0)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() { _ = router.RunDigest(ctx, DigestPolicy{Interval: 10 * time.Millisecond}) }()
+
+			router.Enqueue("a")
+			router.Enqueue("b")
+			Eventually(services[0].sent).Should(Equal([]string{"a\nb"}))
+		})
+	})
+})
+
+func init() {
+	MustRegister("digest-test", func() t.Service { return &digestTestService{} })
+}
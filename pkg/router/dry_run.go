@@ -0,0 +1,174 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+	"github.com/containrrr/shoutrrr/pkg/util/redact"
+)
+
+// Request is a HTTP request that a service would have made, as reported by dry runs
+type Request struct {
+	Method string `json:"method"`
+	// URL is the request URL, with the secrets of the service redacted
+	URL string `json:"url"`
+	// Header are the request headers, with the secrets of the service redacted
+	Header http.Header `json:"headers,omitempty"`
+	// Body is the request body, with the secrets of the service redacted
+	Body string `json:"body,omitempty"`
+}
+
+// sensitiveHeaders are the headers whose values are always redacted, unless ShowSecrets is set
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+type dryRunKey struct{}
+
+// isDryRun returns whether ctx belongs to a dry run
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// DryRun processes the message in the same way as Send, but reports the requests that each service would make in the
+// Requests of its result instead of sending it
+func (router *ServiceRouter) DryRun(message string, params *t.Params) []SendResult {
+	return router.DryRunContext(context.Background(), message, params)
+}
+
+// DryRunContext processes the message in the same way as SendContextWithResults, applying the template, middlewares,
+// rules, strategy and format of the router, but instead of sending it, the services build the requests they would
+// make, which are reported in the Requests of their results. The duplicate suppression, hooks, rate limits and retries
+// of the router are not used. Services that cannot build their requests without sending them, which are the ones that
+// does not implement types.RequestBuilder, report an error wrapping types.ErrDryRunUnsupported.
+func (router *ServiceRouter) DryRunContext(ctx context.Context, message string, params *t.Params) []SendResult {
+	if router == nil {
+		return []SendResult{{Err: fmt.Errorf("error sending message: no senders")}}
+	}
+
+	return router.sendThrough(context.WithValue(ctx, dryRunKey{}, true), plainNotification(message), params).collect()
+}
+
+// startDryRun starts building the requests for the notification using the service, writing the result to results
+func (rs routedService) startDryRun(ctx context.Context, index int, content notification, params t.Params, formatErr error, results chan<- serviceResult) {
+	go func() {
+		result := rs.newResult()
+		result.dryRun = true
+
+		err := formatErr
+		if err == nil {
+			result.Requests, err = rs.buildRequests(ctx, content, params)
+		}
+		result.Err = rs.redactError(err)
+
+		results <- serviceResult{index, result}
+	}()
+}
+
+// buildRequests returns the redacted requests for all the parts of the notification
+func (rs routedService) buildRequests(ctx context.Context, content notification, params t.Params) ([]Request, error) {
+	var requests []Request
+	for _, part := range content.parts(rs.Service) {
+		built, err := part.buildUsing(ctx, rs.Service, params)
+		if err != nil {
+			return requests, err
+		}
+
+		for _, req := range built {
+			request, err := rs.dryRunRequest(req)
+			if err != nil {
+				return requests, err
+			}
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// buildUsing returns the requests that the service would make when sending the notification, as sendUsing would
+func (n notification) buildUsing(ctx context.Context, service t.Service, params t.Params) ([]*http.Request, error) {
+	name := serviceName(service)
+	if n.rich {
+		return nil, fmt.Errorf("%w by %v for message items", t.ErrDryRunUnsupported, name)
+	}
+
+	message := n.message
+	if n.markdown {
+		if _, isMarkdownSender := service.(t.MarkdownSender); isMarkdownSender {
+			builder, ok := service.(t.MarkdownRequestBuilder)
+			if !ok {
+				return nil, fmt.Errorf("%w by %v for Markdown messages", t.ErrDryRunUnsupported, name)
+			}
+			return builder.BuildMarkdownRequests(ctx, message, &params)
+		}
+		message = markdown.ToPlain(message)
+	}
+
+	builder, ok := service.(t.RequestBuilder)
+	if !ok {
+		return nil, fmt.Errorf("%w by %v", t.ErrDryRunUnsupported, name)
+	}
+	return builder.BuildRequests(ctx, message, &params)
+}
+
+// dryRunRequest returns the request as reported by dry runs, with the secrets of the service redacted
+func (rs routedService) dryRunRequest(req *http.Request) (Request, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return Request{}, fmt.Errorf("failed to read the request body: %w", err)
+	}
+
+	redactString := func(s string) string { return s }
+	redactURL := redactString
+	if rs.redactor != nil {
+		redactString = rs.redactor.String
+		redactURL = rs.redactor.URL
+	}
+
+	header := make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		redacted := make([]string, len(values))
+		for i, value := range values {
+			redacted[i] = redactString(value)
+		}
+		header[key] = redacted
+	}
+	if !rs.showSecrets {
+		for _, key := range sensitiveHeaders {
+			if header.Get(key) != "" {
+				header.Set(key, redact.Placeholder)
+			}
+		}
+	}
+
+	return Request{
+		Method: req.Method,
+		URL:    redactURL(req.URL.String()),
+		Header: header,
+		Body:   redactString(body),
+	}, nil
+}
+
+// requestBody returns the body of the request, without consuming it if it can be read again
+func requestBody(req *http.Request) (string, error) {
+	var body io.ReadCloser
+	switch {
+	case req.GetBody != nil:
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return "", err
+		}
+	case req.Body != nil && req.Body != http.NoBody:
+		body = req.Body
+	default:
+		return "", nil
+	}
+
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	return string(content), err
+}
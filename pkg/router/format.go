@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+)
+
+const (
+	// FormatKey is the service URL query key and param used for setting the format of the message text
+	FormatKey = "format"
+	// PlainFormat is the format of messages that are sent as is, which is the default
+	PlainFormat = "plain"
+	// MarkdownFormat is the format of Markdown messages, which are converted to the native formatting of each service
+	MarkdownFormat = "markdown"
+)
+
+// parseFormat returns the message format with the name, or an error if it is not a known format
+func parseFormat(name string) (string, error) {
+	switch format := strings.ToLower(name); format {
+	case PlainFormat, MarkdownFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown message format %q, expected %q or %q", name, PlainFormat, MarkdownFormat)
+	}
+}
+
+// formatContent returns the notification in the format set by the format param, or the format option of the service
+// if the param is not set, and the params without the format param, which is never passed on to the service
+func (rs routedService) formatContent(content notification, params t.Params) (notification, t.Params, error) {
+	format := rs.options.format
+	if name, found := params[FormatKey]; found {
+		var err error
+		if format, err = parseFormat(name); err != nil {
+			return content, params, err
+		}
+
+		withoutFormat := make(t.Params, len(params))
+		for key, value := range params {
+			withoutFormat[key] = value
+		}
+		delete(withoutFormat, FormatKey)
+		params = withoutFormat
+	}
+
+	if format != MarkdownFormat {
+		return content, params, nil
+	}
+	return content.asMarkdown(), params, nil
+}
+
+// asMarkdown returns the notification with the text marked as Markdown. Since the message items of rich notifications
+// are rendered by the services themselves, their text is converted to plain text instead.
+func (n notification) asMarkdown() notification {
+	if !n.rich {
+		n.markdown = true
+		return n
+	}
+
+	items := make([]t.MessageItem, len(n.items))
+	copy(items, n.items)
+	for i := range items {
+		items[i].Text = markdown.ToPlain(items[i].Text)
+	}
+	n.items = items
+	return n
+}
+
+// sendMarkdown sends the Markdown message using the native formatting of the service if it implements MarkdownSender,
+// and otherwise as plain text
+func sendMarkdown(ctx context.Context, service t.Service, message string, params t.Params) error {
+	if sender, ok := service.(t.MarkdownSender); ok {
+		return sender.SendMarkdownContext(ctx, message, &params)
+	}
+	return AsContextSender(service).SendContext(ctx, markdown.ToPlain(message), &params)
+}
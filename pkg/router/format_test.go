@@ -0,0 +1,111 @@
+package router
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// markdownTestService records the messages it was sent, and whether they were sent as Markdown
+type markdownTestService struct {
+	standard.Standard
+	lock     sync.Mutex
+	plain    []string
+	markdown []string
+	params   []t.Params
+}
+
+func (service *markdownTestService) Initialize(_ *url.URL, _ t.StdLogger) error {
+	return nil
+}
+
+func (service *markdownTestService) Send(message string, params *t.Params) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	service.plain = append(service.plain, message)
+	service.params = append(service.params, *params)
+	return nil
+}
+
+func (service *markdownTestService) SendMarkdownContext(_ context.Context, message string, params *t.Params) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	service.markdown = append(service.markdown, message)
+	service.params = append(service.params, *params)
+	return nil
+}
+
+func newFormatRouter(serviceURL string) (*ServiceRouter, *markdownTestService) {
+	router, err := New(nil, serviceURL)
+	Expect(err).NotTo(HaveOccurred())
+	return router, router.services[0].Service.(*markdownTestService)
+}
+
+var _ = Describe("the message formats", func() {
+	BeforeEach(resetSends)
+
+	It("should send plain messages as is by default", func() {
+		router, service := newFormatRouter("markdown-test://")
+		Expect(router.Send("**bold**", nil)).To(Equal([]error{nil}))
+		Expect(service.plain).To(Equal([]string{"**bold**"}))
+		Expect(service.markdown).To(BeEmpty())
+	})
+
+	It("should send Markdown messages using the format of the service when set in the URL", func() {
+		router, service := newFormatRouter("markdown-test://?format=markdown")
+		Expect(router.Send("**bold**", nil)).To(Equal([]error{nil}))
+		Expect(service.markdown).To(Equal([]string{"**bold**"}))
+		Expect(service.plain).To(BeEmpty())
+	})
+
+	It("should use the format param without passing it to the service", func() {
+		router, service := newFormatRouter("markdown-test://?format=markdown")
+		Expect(router.Send("**bold**", &t.Params{FormatKey: "plain", "title": "title"})).To(Equal([]error{nil}))
+		Expect(router.Send("**bold**", &t.Params{FormatKey: "markdown"})).To(Equal([]error{nil}))
+		Expect(service.plain).To(Equal([]string{"**bold**"}))
+		Expect(service.markdown).To(Equal([]string{"**bold**"}))
+		Expect(service.params).To(Equal([]t.Params{{"title": "title"}, {}}))
+	})
+
+	It("should send plain text to services that does not support Markdown", func() {
+		router := newRulesRouter(nil, "a")
+		Expect(router.Send("# Alert\n\n**disk** is [full](https://example.com)", &t.Params{FormatKey: "markdown"})).
+			To(Equal([]error{nil}))
+		Expect(sentMessages()).To(Equal([]string{"Alert\n\ndisk is full (https://example.com)"}))
+	})
+
+	It("should convert the text of message items to plain text", func() {
+		router := newRulesRouter(nil, "a")
+		router.SendItems([]t.MessageItem{{Text: "**first**"}, {Text: "_second_"}}, t.Params{FormatKey: "markdown"})
+		Expect(sentMessages()).To(Equal([]string{"first\nsecond"}))
+	})
+
+	It("should keep the format param for templated messages", func() {
+		router, err := New(nil, "ntfy://ntfy.sh/topic")
+		Expect(err).NotTo(HaveOccurred())
+
+		params := t.Params{FormatKey: "markdown", "env": "prod"}
+		Expect(router.services[0].sendParams(params, true)).To(Equal(t.Params{FormatKey: "markdown"}))
+	})
+
+	It("should return an error for unknown formats", func() {
+		_, err := New(nil, "markdown-test://?format=rtf")
+		Expect(err).To(MatchError(ContainSubstring(`unknown message format "rtf"`)))
+
+		router, service := newFormatRouter("markdown-test://")
+		Expect(router.Send("message", &t.Params{FormatKey: "rtf"})).To(HaveExactElements(
+			MatchError(ContainSubstring(`unknown message format "rtf"`)),
+		))
+		Expect(service.plain).To(BeEmpty())
+	})
+})
+
+func init() {
+	MustRegister("markdown-test", func() t.Service { return &markdownTestService{} })
+}
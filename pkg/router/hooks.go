@@ -0,0 +1,62 @@
+package router
+
+import (
+	"context"
+)
+
+// SendInfo identifies the service used for a send, as passed to hooks before sending
+type SendInfo struct {
+	// Scheme is the scheme of the service URL, identifying the service
+	Scheme string
+	// URL is the service URL, with its secrets redacted unless ShowSecrets was set when the service was added
+	URL string
+}
+
+// Hook is notified before and after every send using one of the routers services. Services that are skipped are not
+// reported to the hooks, while retries are included in a single send.
+type Hook interface {
+	// BeforeSend is called before sending using the service. The returned context is used for the send, and is passed
+	// to AfterSend of the same hook.
+	BeforeSend(ctx context.Context, info SendInfo) context.Context
+	// AfterSend is called with the result when the send using the service has completed
+	AfterSend(ctx context.Context, result SendResult)
+}
+
+// HookFuncs implements Hook using optional functions
+type HookFuncs struct {
+	Before func(ctx context.Context, info SendInfo) context.Context
+	After  func(ctx context.Context, result SendResult)
+}
+
+// BeforeSend calls the Before function, if it is set
+func (hf HookFuncs) BeforeSend(ctx context.Context, info SendInfo) context.Context {
+	if hf.Before == nil {
+		return ctx
+	}
+	return hf.Before(ctx, info)
+}
+
+// AfterSend calls the After function, if it is set
+func (hf HookFuncs) AfterSend(ctx context.Context, result SendResult) {
+	if hf.After != nil {
+		hf.After(ctx, result)
+	}
+}
+
+// beforeSend calls BeforeSend of the hooks in order, where every hook is given the context returned by the previous
+// one. The context returned by the last hook is returned together with the contexts returned by each of the hooks.
+func beforeSend(ctx context.Context, hooks []Hook, info SendInfo) (context.Context, []context.Context) {
+	contexts := make([]context.Context, len(hooks))
+	for i, hook := range hooks {
+		ctx = hook.BeforeSend(ctx, info)
+		contexts[i] = ctx
+	}
+	return ctx, contexts
+}
+
+// afterSend calls AfterSend of the hooks in reverse order, with the context returned by their BeforeSend
+func afterSend(hooks []Hook, contexts []context.Context, result SendResult) {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].AfterSend(contexts[i], result)
+	}
+}
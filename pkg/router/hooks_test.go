@@ -0,0 +1,89 @@
+package router
+
+import (
+	"context"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type hookKey string
+
+var _ = Describe("the send hooks", func() {
+	It("should call the hooks around every send in order", func() {
+		router, err := New(nil, "result-test://a")
+		Expect(err).NotTo(HaveOccurred())
+
+		var calls []string
+		hook := func(name string) Hook {
+			return HookFuncs{
+				Before: func(ctx context.Context, info SendInfo) context.Context {
+					calls = append(calls, "before "+name+" "+info.URL)
+					return context.WithValue(ctx, hookKey(name), name)
+				},
+				After: func(ctx context.Context, result SendResult) {
+					Expect(ctx.Value(hookKey(name))).To(Equal(name))
+					Expect(result.MessageIDs).To(Equal([]string{"id-a"}))
+					calls = append(calls, "after "+name)
+				},
+			}
+		}
+		router.Hooks = []Hook{hook("first"), hook("second")}
+
+		Expect(router.Send("message", nil)).To(Equal([]error{nil}))
+		Expect(calls).To(Equal([]string{
+			"before first result-test://a",
+			"before second result-test://a",
+			"after second",
+			"after first",
+		}))
+	})
+
+	It("should pass the context returned by the hooks to the send", func() {
+		router, err := New(nil, "result-test://a")
+		Expect(err).NotTo(HaveOccurred())
+
+		var sendCtx context.Context
+		router.Hooks = []Hook{HookFuncs{
+			Before: func(ctx context.Context, _ SendInfo) context.Context {
+				return context.WithValue(ctx, hookKey("send"), "value")
+			},
+			After: func(ctx context.Context, _ SendResult) {
+				sendCtx = ctx
+			},
+		}}
+
+		router.Send("message", nil)
+		Expect(sendCtx.Value(hookKey("send"))).To(Equal("value"))
+	})
+
+	It("should report the failed sends", func() {
+		router, err := New(nil, "result-test://fail")
+		Expect(err).NotTo(HaveOccurred())
+
+		var results []SendResult
+		router.Hooks = []Hook{HookFuncs{After: func(_ context.Context, result SendResult) {
+			results = append(results, result)
+		}}}
+
+		router.Send("message", nil)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Err).To(MatchError("send failed"))
+		Expect(results[0].Attempts).To(Equal(1))
+	})
+
+	It("should not call the hooks for skipped services", func() {
+		router := newRulesRouter([]Rule{{Levels: []t.MessageLevel{t.Error}, Services: []string{"pager"}}}, "chat", "pager")
+
+		var urls []string
+		router.Hooks = []Hook{HookFuncs{Before: func(ctx context.Context, info SendInfo) context.Context {
+			urls = append(urls, info.URL)
+			return ctx
+		}}}
+
+		router.Send("message", &t.Params{LevelKey: "error"})
+		Expect(urls).To(Equal([]string{"strategy-test://pager"}))
+	})
+})
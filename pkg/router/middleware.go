@@ -0,0 +1,205 @@
+package router
+
+import (
+	"context"
+	"regexp"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// Message is the content of a send as seen by middlewares, which may modify it before passing it on
+type Message struct {
+	// Text is the plain message. It is empty for rich messages, which uses Items instead.
+	Text string
+	// Items are the message items of rich messages
+	Items []t.MessageItem
+	// Params are the params passed to the services
+	Params    t.Params
+	rich      bool
+	digest    bool
+	templated bool
+}
+
+// Rich returns whether the message consists of message items rather than a plain text
+func (m Message) Rich() bool {
+	return m.rich
+}
+
+// MapText replaces the plain text, or the text of every message item, with the result of calling mapping with it
+func (m *Message) MapText(mapping func(text string) string) {
+	if !m.rich {
+		m.Text = mapping(m.Text)
+		return
+	}
+	for i := range m.Items {
+		m.Items[i].Text = mapping(m.Items[i].Text)
+	}
+}
+
+// newMessage returns a message with copies of the notification content and params, allowing middlewares to modify
+// them without affecting the caller
+func newMessage(content notification, params *t.Params) Message {
+	message := Message{Text: content.message, rich: content.rich, digest: content.digest, Params: t.Params{}}
+	if content.rich {
+		message.Items = make([]t.MessageItem, len(content.items))
+		for i, item := range content.items {
+			item.Fields = append([]t.Field(nil), item.Fields...)
+			message.Items[i] = item
+		}
+	}
+	if params != nil {
+		for key, value := range *params {
+			message.Params[key] = value
+		}
+	}
+	return message
+}
+
+// notification returns the content of the message
+func (m Message) notification() notification {
+	if m.rich {
+		return notification{items: m.Items, rich: true, templated: m.templated}
+	}
+	return notification{message: m.Text, digest: m.digest, templated: m.templated}
+}
+
+// SendFunc sends the message using the routers services. It returns once the sends have been started, without
+// waiting for them to complete.
+type SendFunc func(ctx context.Context, message Message) error
+
+// Middleware wraps the SendFunc of the next middleware, or of the router itself. A middleware can modify the message
+// before passing it on to next, or short-circuit the send by not calling next. If it returns without calling next,
+// all services are reported as skipped if the error is nil, or as failed with the error otherwise.
+type Middleware func(next SendFunc) SendFunc
+
+// Chain returns a middleware that passes the message through the middlewares in order
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next SendFunc) SendFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// Prefix returns a middleware that prepends the prefix to plain messages, or to the text of the first message item
+func Prefix(prefix string) Middleware {
+	return transform(func(message *Message) {
+		if !message.rich {
+			message.Text = prefix + message.Text
+		} else if len(message.Items) > 0 {
+			message.Items[0].Text = prefix + message.Items[0].Text
+		}
+	})
+}
+
+// Suffix returns a middleware that appends the suffix to plain messages, or to the text of the last message item
+func Suffix(suffix string) Middleware {
+	return transform(func(message *Message) {
+		if !message.rich {
+			message.Text += suffix
+		} else if last := len(message.Items) - 1; last >= 0 {
+			message.Items[last].Text += suffix
+		}
+	})
+}
+
+// ansiPattern matches ANSI escape sequences, like the ones used for colouring terminal output
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSI returns a middleware that removes ANSI escape sequences, like colour codes, from the message text, the
+// fields of message items and the title param
+func StripANSI() Middleware {
+	strip := func(text string) string {
+		return ansiPattern.ReplaceAllString(text, "")
+	}
+	return transform(func(message *Message) {
+		message.MapText(strip)
+		for i := range message.Items {
+			for f, field := range message.Items[i].Fields {
+				message.Items[i].Fields[f].Value = strip(field.Value)
+			}
+		}
+		if title, found := message.Params["title"]; found {
+			message.Params["title"] = strip(title)
+		}
+	})
+}
+
+// truncationSuffix is appended to texts that have been truncated
+const truncationSuffix = "…"
+
+// Truncate returns a middleware that limits the plain message, or the text of every message item, to maxLength
+// characters. Texts that are cut short end with an ellipsis, which is included in the length.
+func Truncate(maxLength int) Middleware {
+	return transform(func(message *Message) {
+		message.MapText(func(text string) string {
+			runes := []rune(text)
+			if len(runes) <= maxLength {
+				return text
+			}
+			if maxLength < 1 {
+				return ""
+			}
+			return string(runes[:maxLength-1]) + truncationSuffix
+		})
+	})
+}
+
+// ParamDefaults returns a middleware that adds the params that are not already set for the message. Note that the
+// params are passed to all services, which fail to send if they do not support them.
+func ParamDefaults(defaults t.Params) Middleware {
+	return transform(func(message *Message) {
+		for key, value := range defaults {
+			if _, found := message.Params[key]; !found {
+				message.Params[key] = value
+			}
+		}
+	})
+}
+
+// transform returns a middleware that modifies every message using the function before passing it on
+func transform(modify func(message *Message)) Middleware {
+	return func(next SendFunc) SendFunc {
+		return func(ctx context.Context, message Message) error {
+			modify(&message)
+			return next(ctx, message)
+		}
+	}
+}
+
+// sendThrough starts sending the notification using the current services of the router, after rendering it using the
+// message template and passing it through the routers Middlewares
+func (router *ServiceRouter) sendThrough(ctx context.Context, content notification, params *t.Params) *pendingSend {
+	send := router.newSend()
+	started := false
+	sendFunc := func(ctx context.Context, message Message) error {
+		if !started {
+			started = true
+			router.sendAll(ctx, send, message.notification(), message.Params)
+		}
+		return nil
+	}
+
+	message := newMessage(content, params)
+	if tpl := router.messageTemplate(ctx); tpl != nil {
+		if err := message.render(tpl); err != nil {
+			send.skipAll(err)
+			return send
+		}
+	}
+
+	err := Chain(router.Middlewares...)(sendFunc)(ctx, message)
+	if started {
+		if err != nil {
+			router.log("Middleware failed after sending:", err)
+		}
+		return send
+	}
+
+	if err == nil {
+		router.log("Message was dropped by middleware")
+	}
+	send.skipAll(err)
+	return send
+}
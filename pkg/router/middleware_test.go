@@ -0,0 +1,145 @@
+package router
+
+import (
+	"context"
+	"errors"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// applyMiddleware returns the message that is passed on by the middleware
+func applyMiddleware(middleware Middleware, message Message) Message {
+	var passed Message
+	err := middleware(func(_ context.Context, message Message) error {
+		passed = message
+		return nil
+	})(context.Background(), message)
+	Expect(err).NotTo(HaveOccurred())
+	return passed
+}
+
+func richMessage(texts ...string) Message {
+	items := make([]t.MessageItem, len(texts))
+	for i, text := range texts {
+		items[i] = t.MessageItem{Text: text}
+	}
+	return newMessage(richNotification(items), nil)
+}
+
+var _ = Describe("the middlewares", func() {
+	BeforeEach(resetSends)
+
+	It("should be applied to messages in order before sending", func() {
+		router := newRulesRouter(nil, "a")
+		router.Middlewares = []Middleware{Prefix("host: "), Truncate(10)}
+
+		Expect(router.Send("message text", nil)).To(Equal([]error{nil}))
+		Expect(sentMessages()).To(Equal([]string{"host: mes…"}))
+	})
+
+	It("should not modify the callers params or items", func() {
+		router := newRulesRouter(nil, "a")
+		router.Middlewares = []Middleware{ParamDefaults(t.Params{"env": "prod"}), Prefix("host: ")}
+
+		params := t.Params{}
+		items := []t.MessageItem{{Text: "item"}}
+		router.SendItems(items, params)
+		Expect(params).To(BeEmpty())
+		Expect(items[0].Text).To(Equal("item"))
+		Expect(sentMessages()).To(Equal([]string{"host: item"}))
+	})
+
+	It("should report all services as skipped when a middleware drops the message", func() {
+		router := newRulesRouter(nil, "a", "b")
+		router.Middlewares = []Middleware{func(next SendFunc) SendFunc {
+			return func(ctx context.Context, message Message) error {
+				if message.Text == "noise" {
+					return nil
+				}
+				return next(ctx, message)
+			}
+		}}
+
+		results := router.SendWithResults("noise", nil)
+		Expect(results[0].Skipped()).To(BeTrue())
+		Expect(results[1].Skipped()).To(BeTrue())
+		Expect(sentHosts()).To(BeEmpty())
+
+		router.Send("signal", nil)
+		Expect(sentHosts()).To(ConsistOf("a", "b"))
+	})
+
+	It("should report the error of a middleware that rejects the message for all services", func() {
+		router := newRulesRouter(nil, "a", "b")
+		router.Middlewares = []Middleware{func(next SendFunc) SendFunc {
+			return func(context.Context, Message) error {
+				return errors.New("rejected")
+			}
+		}}
+
+		Expect(router.Send("message", nil)).To(HaveEach(MatchError("rejected")))
+		Expect(sentHosts()).To(BeEmpty())
+	})
+
+	It("should pass the modified params to the services", func() {
+		router := newRulesRouter([]Rule{{Levels: []t.MessageLevel{t.Error}, Services: []string{"pager"}}}, "chat", "pager")
+		router.Middlewares = []Middleware{ParamDefaults(t.Params{LevelKey: "error"})}
+
+		router.Send("message", nil)
+		Expect(sentHosts()).To(Equal([]string{"pager"}))
+	})
+
+	Describe("Prefix and Suffix", func() {
+		It("should add the text to plain messages", func() {
+			message := applyMiddleware(Chain(Prefix("["), Suffix("]")), Message{Text: "text"})
+			Expect(message.Text).To(Equal("[text]"))
+		})
+
+		It("should add the text to the first and last message items", func() {
+			message := applyMiddleware(Chain(Prefix("["), Suffix("]")), richMessage("a", "b", "c"))
+			Expect(message.Items[0].Text).To(Equal("[a"))
+			Expect(message.Items[1].Text).To(Equal("b"))
+			Expect(message.Items[2].Text).To(Equal("c]"))
+		})
+	})
+
+	Describe("StripANSI", func() {
+		It("should remove colour codes from the message, fields and title", func() {
+			message := Message{Text: "\x1b[1;31mfailed\x1b[0m: \x1b]8;;https://example.com\x07link\x1b]8;;\x07", Params: t.Params{"title": "\x1b[32mok\x1b[m"}}
+			message = applyMiddleware(StripANSI(), message)
+			Expect(message.Text).To(Equal("failed: link"))
+			Expect(message.Params["title"]).To(Equal("ok"))
+
+			rich := richMessage("\x1b[33mwarning\x1b[0m")
+			rich.Items[0].Fields = []t.Field{{Key: "status", Value: "\x1b[31mdown\x1b[0m"}}
+			rich = applyMiddleware(StripANSI(), rich)
+			Expect(rich.Items[0].Text).To(Equal("warning"))
+			Expect(rich.Items[0].Fields[0].Value).To(Equal("down"))
+		})
+	})
+
+	Describe("Truncate", func() {
+		It("should cut long texts and append an ellipsis", func() {
+			Expect(applyMiddleware(Truncate(5), Message{Text: "short"}).Text).To(Equal("short"))
+			Expect(applyMiddleware(Truncate(5), Message{Text: "too long"}).Text).To(Equal("too …"))
+			Expect(applyMiddleware(Truncate(3), Message{Text: "åäöü"}).Text).To(Equal("åä…"))
+		})
+
+		It("should cut the text of every message item", func() {
+			message := applyMiddleware(Truncate(3), richMessage("abc", "abcd"))
+			Expect(message.Items[0].Text).To(Equal("abc"))
+			Expect(message.Items[1].Text).To(Equal("ab…"))
+		})
+	})
+
+	Describe("ParamDefaults", func() {
+		It("should only add params that are not set", func() {
+			message := Message{Params: t.Params{"title": "custom"}}
+			message = applyMiddleware(ParamDefaults(t.Params{"title": "default", "env": "prod"}), message)
+			Expect(message.Params).To(Equal(t.Params{"title": "custom", "env": "prod"}))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// notification is the content of a send, either a plain message or a set of rich message items. Digests are plain
+// messages consisting of queued messages on separate lines. Templated notifications have been rendered using a message
+// template, which has used the params. Markdown notifications are plain messages that are converted to the native
+// formatting of each service.
+type notification struct {
+	message   string
+	items     []t.MessageItem
+	rich      bool
+	digest    bool
+	templated bool
+	markdown  bool
+}
+
+func plainNotification(message string) notification {
+	return notification{message: message}
+}
+
+func richNotification(items []t.MessageItem) notification {
+	return notification{items: items, rich: true}
+}
+
+// sendUsing sends the notification using the service, falling back to a plain message for rich notifications, and to
+// plain text for Markdown notifications, if the service does not support them
+func (n notification) sendUsing(ctx context.Context, service t.Service, params t.Params) error {
+	if n.rich {
+		return AsRichContextSender(service).SendItemsContext(ctx, n.items, params)
+	}
+	if n.markdown {
+		return sendMarkdown(ctx, service, n.message, params)
+	}
+	return AsContextSender(service).SendContext(ctx, n.message, &params)
+}
+
+// text returns the plain text of the notification
+func (n notification) text() string {
+	if n.rich {
+		return strings.TrimSuffix(t.ItemsToPlain(n.items), "\n")
+	}
+	return n.message
+}
+
+// level returns the level of the notification, which is the level param if it is set, or otherwise the highest level
+// of the message items
+func (n notification) level(params t.Params) t.MessageLevel {
+	if name, found := params[LevelKey]; found {
+		level, _ := t.ParseMessageLevel(name)
+		return level
+	}
+
+	level := t.Unknown
+	for _, item := range n.items {
+		if item.Level > level {
+			level = item.Level
+		}
+	}
+	return level
+}
+
+// identity returns a string that is the same for notifications that are considered to be duplicates. Timestamps are
+// not included, since they usually differ between repeats.
+func (n notification) identity() string {
+	if !n.rich {
+		return n.message
+	}
+
+	sb := strings.Builder{}
+	for _, item := range n.items {
+		_, _ = fmt.Fprintf(&sb, "%d:%s%d:", len(item.Text), item.Text, item.Level)
+		for _, field := range item.Fields {
+			_, _ = fmt.Fprintf(&sb, "%d:%s%d:%s", len(field.Key), field.Key, len(field.Value), field.Value)
+		}
+		for _, attachment := range item.Attachments {
+			_, _ = fmt.Fprintf(&sb, "%d:%s%d:%s", len(attachment.Name), attachment.Name, len(attachment.Content), attachment.Content)
+		}
+		sb.WriteRune(';')
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/util/ratelimit"
+)
+
+// defaultRateLimits are the rate limits used for the built-in services, unless overridden in the service URL. They
+// are slightly below the limits enforced by the providers.
+var defaultRateLimits = map[string]ratelimit.Limit{
+	"discord":  {Count: 30, Per: time.Minute},
+	"slack":    {Count: 1, Per: time.Second},
+	"telegram": {Count: 1, Per: time.Second},
+}
+
+// defaultRateLimit returns the default rate limit for the service scheme
+func defaultRateLimit(scheme string) ratelimit.Limit {
+	scheme = strings.ToLower(scheme)
+
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	if limit := registrations[scheme].rateLimit; limit != nil {
+		return *limit
+	}
+	return defaultRateLimits[scheme]
+}
+
+// serviceRateLimit returns the rate limit for the service, using the default rate limit of the scheme unless an
+// override is given
+func serviceRateLimit(scheme string, override *ratelimit.Limit) ratelimit.Limit {
+	if override != nil {
+		return *override
+	}
+	return defaultRateLimit(scheme)
+}
+
+// limiterKey returns the key of the rate limiter for the service URL, which is shared by the services of a router that
+// use the same URL and limit. Returns an empty key if the service should not be rate limited.
+func limiterKey(limit ratelimit.Limit, serviceURL *url.URL) string {
+	if limit.IsUnlimited() {
+		return ""
+	}
+	return limit.String() + " " + serviceURL.String()
+}
+
+// setServices replaces the routers services, assigning them the limiters of the router for their URLs, and removes
+// the limiters that are no longer used by any of the services. Must be called with the lock held.
+func (router *ServiceRouter) setServices(services []routedService) {
+	limiters := make(map[string]*ratelimit.Limiter, len(services))
+	for i, rs := range services {
+		if rs.limiterKey == "" {
+			continue
+		}
+
+		limiter, found := limiters[rs.limiterKey]
+		if !found {
+			limiter = rs.limiterFrom(router.limiters)
+			limiters[rs.limiterKey] = limiter
+		}
+		services[i].limiter = limiter
+	}
+
+	router.limiters = limiters
+	router.services = services
+}
+
+// limiterFrom returns the limiter for the service from limiters, or a new limiter if there is none
+func (rs routedService) limiterFrom(limiters map[string]*ratelimit.Limiter) *ratelimit.Limiter {
+	if rs.limiterKey == "" {
+		return nil
+	}
+	if limiter, found := limiters[rs.limiterKey]; found {
+		return limiter
+	}
+	return ratelimit.NewLimiter(rs.limit)
+}
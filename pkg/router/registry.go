@@ -0,0 +1,141 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/ratelimit"
+)
+
+// ServiceDocs contains the documentation metadata of a registered service
+type ServiceDocs struct {
+	// Description is a short summary of the service, shown above its config documentation
+	Description string
+	// Link is an URL pointing to further documentation of the service
+	Link string
+}
+
+// RegisterOption configures optional metadata for a service registration
+type RegisterOption func(*registration)
+
+// WithGenerator sets the generator that is used by default when generating URLs for the service
+func WithGenerator(factory func() t.Generator) RegisterOption {
+	return func(reg *registration) {
+		reg.generator = factory
+	}
+}
+
+// WithDocs sets the documentation metadata for the service
+func WithDocs(docs ServiceDocs) RegisterOption {
+	return func(reg *registration) {
+		reg.docs = &docs
+	}
+}
+
+// WithRateLimit sets the default rate limit for the service, used unless it is overridden in the service URL
+func WithRateLimit(limit ratelimit.Limit) RegisterOption {
+	return func(reg *registration) {
+		reg.rateLimit = &limit
+	}
+}
+
+type registration struct {
+	generator func() t.Generator
+	docs      *ServiceDocs
+	rateLimit *ratelimit.Limit
+}
+
+var (
+	registryLock sync.RWMutex
+	// registrations contains the optional metadata of services added using Register
+	registrations = map[string]registration{}
+	// schemePattern matches the scheme names that can be used in service URLs, excluding '+' since it is used for
+	// custom URLs
+	schemePattern = regexp.MustCompile(`^[a-z][a-z0-9.-]*$`)
+)
+
+// Register adds a service, making it available to all routers using the specified scheme. Schemes are case-insensitive.
+// An error is returned if the scheme is invalid or if it is already used by another service.
+func Register(scheme string, factory func() t.Service, options ...RegisterOption) error {
+	scheme = strings.ToLower(scheme)
+	if !schemePattern.MatchString(scheme) {
+		return fmt.Errorf("invalid service scheme %q", scheme)
+	}
+	if factory == nil {
+		return fmt.Errorf("no factory given for service %q", scheme)
+	}
+
+	reg := registration{}
+	for _, option := range options {
+		option(&reg)
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, exists := serviceMap[scheme]; exists {
+		return fmt.Errorf("a service is already registered for the scheme %q", scheme)
+	}
+
+	serviceMap[scheme] = factory
+	registrations[scheme] = reg
+	return nil
+}
+
+// MustRegister calls Register, panicking if the service cannot be registered. It is meant to be used from init
+// functions, where a conflicting registration is a programming error.
+func MustRegister(scheme string, factory func() t.Service, options ...RegisterOption) {
+	if err := Register(scheme, factory, options...); err != nil {
+		panic(err)
+	}
+}
+
+// NewServiceGenerator returns a new instance of the generator registered for the service scheme, if any
+func NewServiceGenerator(scheme string) (t.Generator, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	factory := registrations[strings.ToLower(scheme)].generator
+	if factory == nil {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// GetServiceDocs returns the documentation metadata registered for the service scheme, if any
+func GetServiceDocs(scheme string) (ServiceDocs, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	docs := registrations[strings.ToLower(scheme)].docs
+	if docs == nil {
+		return ServiceDocs{}, false
+	}
+	return *docs, true
+}
+
+// lookupServiceFactory returns the factory for the service scheme
+func lookupServiceFactory(scheme string) (func() t.Service, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	factory, found := serviceMap[strings.ToLower(scheme)]
+	return factory, found
+}
+
+// listServiceSchemes returns the schemes of all available services, in alphabetical order
+func listServiceSchemes() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	schemes := make([]string, 0, len(serviceMap))
+	for scheme := range serviceMap {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
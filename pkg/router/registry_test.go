@@ -0,0 +1,82 @@
+package router
+
+import (
+	"github.com/containrrr/shoutrrr/pkg/generators/basic"
+	"github.com/containrrr/shoutrrr/pkg/services/logger"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var newLoggerService = func() t.Service { return &logger.Service{} }
+
+var _ = Describe("the service registry", func() {
+	When("registering a new service", func() {
+		It("should make the service available to the router", func() {
+			Expect(Register("Registry-Test", newLoggerService)).To(Succeed())
+
+			Expect(sr.ListServices()).To(ContainElement("registry-test"))
+
+			service, err := sr.NewService("registry-test")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service).To(BeAssignableToTypeOf(&logger.Service{}))
+
+			service, err = sr.Locate("registry-test://")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Send("message", nil)).To(Succeed())
+		})
+
+		It("should return the generator and docs metadata", func() {
+			docs := ServiceDocs{Description: "A test service", Link: "https://example.com/docs"}
+			Expect(Register("registry-meta",
+				newLoggerService,
+				WithGenerator(func() t.Generator { return &basic.Generator{} }),
+				WithDocs(docs),
+			)).To(Succeed())
+
+			generator, found := NewServiceGenerator("registry-meta")
+			Expect(found).To(BeTrue())
+			Expect(generator).To(BeAssignableToTypeOf(&basic.Generator{}))
+
+			registeredDocs, found := GetServiceDocs("registry-meta")
+			Expect(found).To(BeTrue())
+			Expect(registeredDocs).To(Equal(docs))
+		})
+
+		It("should not return any metadata for services registered without it", func() {
+			_, found := NewServiceGenerator("discord")
+			Expect(found).To(BeFalse())
+			_, found = GetServiceDocs("discord")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	When("the registration conflicts with an existing service", func() {
+		It("should return an error for built-in services", func() {
+			Expect(Register("discord", newLoggerService)).To(MatchError(ContainSubstring("already registered")))
+		})
+
+		It("should return an error for previously registered services", func() {
+			Expect(Register("registry-twice", newLoggerService)).To(Succeed())
+			Expect(Register("REGISTRY-TWICE", newLoggerService)).To(MatchError(ContainSubstring("already registered")))
+		})
+
+		It("should panic when using MustRegister", func() {
+			Expect(func() { MustRegister("slack", newLoggerService) }).To(Panic())
+		})
+	})
+
+	When("the registration is invalid", func() {
+		It("should return an error for invalid schemes", func() {
+			Expect(Register("", newLoggerService)).To(HaveOccurred())
+			Expect(Register("custom+https", newLoggerService)).To(HaveOccurred())
+			Expect(Register("1service", newLoggerService)).To(HaveOccurred())
+		})
+
+		It("should return an error if the factory is missing", func() {
+			Expect(Register("registry-nil", nil)).To(HaveOccurred())
+			Expect(sr.ListServices()).NotTo(ContainElement("registry-nil"))
+		})
+	})
+})
@@ -1,21 +1,64 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/containrrr/shoutrrr/pkg/format"
 	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/ratelimit"
+	"github.com/containrrr/shoutrrr/pkg/util/redact"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
-// ServiceRouter is responsible for routing a message to a specific notification service using the notification URL
+// ServiceRouter is responsible for routing a message to a specific notification service using the notification URL.
+// It is safe for concurrent use, and services can be added and removed while messages are being sent, in which case
+// the sends already in progress keep using the services they started with.
 type ServiceRouter struct {
-	logger   t.StdLogger
-	services []t.Service
-	queue    []string
-	Timeout  time.Duration
+	// lock guards the logger, the HTTP clients, the services and their limiters, the rules and the template
+	lock   sync.RWMutex
+	logger t.StdLogger
+	// httpClient is the client used by the services, which is either baseClient or the client created from the HTTP
+	// options of a config
+	httpClient *http.Client
+	// baseClient is the client set using SetHTTPClient, used when no config with HTTP options has been loaded
+	baseClient *http.Client
+	services   []routedService
+	// limiters are the rate limiters of the services, shared by the services that use the same URL
+	limiters  map[string]*ratelimit.Limiter
+	template  *template.Template
+	queueLock sync.Mutex
+	queue     []string
+	// queueFull is signalled when the queue reaches queueMaxItems while RunDigest is running
+	queueFull     chan struct{}
+	queueMaxItems int
+	// Timeout is the maximum duration of each attempt to send a message using a service
+	Timeout time.Duration
+	// RetryPolicy is used for retrying failed sends, unless overridden in the service URL
+	RetryPolicy retry.Policy
+	// ShowSecrets disables the redaction of secret values from logs and errors for services added after it is set
+	ShowSecrets bool
+	// Strategy determines which services are used for delivering messages. Defaults to Broadcast.
+	Strategy Strategy
+	// Rules selects the named services that receive each message. If there are no rules, all services are used.
+	// Use ReplaceRules for changing the rules while messages are being sent.
+	Rules []Rule
+	// Middlewares are applied in order to every message before it is sent, and can modify it or stop it from being sent
+	Middlewares []Middleware
+	// Hooks are notified before and after every send using one of the services, e.g. for collecting metrics
+	Hooks []Hook
+	// Dedup is used for suppressing repeated sends of the same message. Disabled by default.
+	Dedup     DedupPolicy
+	dedupOnce sync.Once
+	dedup     *deduplicator
 }
 
 // New creates a new service router using the specified logger and service URLs
@@ -33,71 +76,198 @@ func New(logger t.StdLogger, serviceURLs ...string) (*ServiceRouter, error) {
 	return &router, nil
 }
 
+// Derive creates a new service router for the specified service URLs, using the logger, HTTP client, template,
+// timeout, retry policy, middlewares and hooks of the router. The strategy, rules and duplicate suppression of the
+// router are not used, since they apply to the services of the router.
+func (router *ServiceRouter) Derive(serviceURLs ...string) (*ServiceRouter, error) {
+	router.lock.RLock()
+	derived := ServiceRouter{
+		logger:      router.logger,
+		httpClient:  router.httpClient,
+		baseClient:  router.baseClient,
+		template:    router.template,
+		limiters:    make(map[string]*ratelimit.Limiter, len(router.limiters)),
+		Timeout:     router.Timeout,
+		RetryPolicy: router.RetryPolicy,
+		ShowSecrets: router.ShowSecrets,
+		Middlewares: router.Middlewares,
+		Hooks:       router.Hooks,
+	}
+	// The services of the derived router share the limiters of the services of the router that use the same URL
+	for key, limiter := range router.limiters {
+		derived.limiters[key] = limiter
+	}
+	router.lock.RUnlock()
+
+	for _, serviceURL := range serviceURLs {
+		if err := derived.AddService(serviceURL); err != nil {
+			return nil, fmt.Errorf("error initializing router services: %s", err)
+		}
+	}
+	return &derived, nil
+}
+
 // AddService initializes the specified service from its URL, and adds it if no errors occur
 func (router *ServiceRouter) AddService(serviceURL string) error {
-	service, err := router.initService(serviceURL)
+	return router.AddNamedService("", serviceURL)
+}
+
+// AddNamedService initializes the specified service from its URL, and adds it using the name if no errors occur.
+// The name is used for selecting the service in routing rules.
+func (router *ServiceRouter) AddNamedService(name string, serviceURL string) error {
+	rs, err := router.initRoutedService(serviceURL)
 	if err == nil {
-		router.services = append(router.services, service)
+		rs.name = name
+		router.addServices(rs)
 	}
 	return err
 }
 
+// addServices appends the services to the routers services. The slice is copied, since it may be in use by sends.
+func (router *ServiceRouter) addServices(services ...routedService) {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	current := router.services
+	router.setServices(append(current[:len(current):len(current)], services...))
+}
+
+// RemoveService removes the services that were added using the service URL, returning whether any were found. Sends
+// that are in progress still use the removed services.
+func (router *ServiceRouter) RemoveService(serviceURL string) bool {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+
+	services := make([]routedService, 0, len(router.services))
+	for _, rs := range router.services {
+		if rs.url != serviceURL {
+			services = append(services, rs)
+		}
+	}
+
+	removed := len(services) < len(router.services)
+	router.setServices(services)
+	return removed
+}
+
+// ReplaceServices replaces all the routers services with the specified service URLs. If any of the services fails to
+// initialize, an error is returned and the current services are kept. Sends that are in progress are not affected.
+func (router *ServiceRouter) ReplaceServices(serviceURLs ...string) error {
+	services := make([]routedService, 0, len(serviceURLs))
+	for _, serviceURL := range serviceURLs {
+		rs, err := router.initRoutedService(serviceURL)
+		if err != nil {
+			return fmt.Errorf("error initializing router services: %s", err)
+		}
+		services = append(services, rs)
+	}
+
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.setServices(services)
+	return nil
+}
+
+// ReplaceRules replaces the routers Rules, which unlike setting the field is safe while messages are being sent
+func (router *ServiceRouter) ReplaceRules(rules []Rule) {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.Rules = rules
+}
+
+// ServiceInfo describes one of the routers services
+type ServiceInfo struct {
+	// Name is the name that the service was added with, if any
+	Name string
+	// Scheme is the scheme of the service URL, identifying the service
+	Scheme string
+	// URL is the service URL, with its secrets redacted unless ShowSecrets was set when the service was added
+	URL string
+}
+
+// Services returns a description of each of the routers services, in the same order as the results of a send
+func (router *ServiceRouter) Services() []ServiceInfo {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+
+	infos := make([]ServiceInfo, len(router.services))
+	for i, rs := range router.services {
+		infos[i] = ServiceInfo{Name: rs.name, Scheme: rs.scheme, URL: rs.redactedURL()}
+	}
+	return infos
+}
+
 // Send sends the specified message using the routers underlying services
 func (router *ServiceRouter) Send(message string, params *t.Params) []error {
+	return router.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends the specified message using the routers underlying services, aborting any pending sends when
+// ctx is done. The returned errors are in the same order as the services were added. Services that were skipped due
+// to the routers Strategy have a nil error, use Delivered to check whether the strategy was satisfied.
+func (router *ServiceRouter) SendContext(ctx context.Context, message string, params *t.Params) []error {
 	if router == nil {
 		return []error{fmt.Errorf("error sending message: no senders")}
 	}
 
-	serviceCount := len(router.services)
-	errors := make([]error, serviceCount)
-	results := router.SendAsync(message, params)
+	return router.sendThrough(ctx, plainNotification(message), params).errors()
+}
+
+// SendWithResults sends the specified message using the routers underlying services, returning the result of each
+// service in the same order as the services were added
+func (router *ServiceRouter) SendWithResults(message string, params *t.Params) []SendResult {
+	return router.SendContextWithResults(context.Background(), message, params)
+}
 
-	for i := range router.services {
-		errors[i] = <-results
+// SendContextWithResults sends the specified message using the routers underlying services, aborting any pending
+// sends when ctx is done. The returned results are in the same order as the services were added.
+func (router *ServiceRouter) SendContextWithResults(ctx context.Context, message string, params *t.Params) []SendResult {
+	if router == nil {
+		return []SendResult{{Err: fmt.Errorf("error sending message: no senders")}}
 	}
 
-	return errors
+	return router.sendThrough(ctx, plainNotification(message), params).collect()
 }
 
 // SendItems sends the specified message items using the routers underlying services
 func (router *ServiceRouter) SendItems(items []t.MessageItem, params t.Params) []error {
+	return router.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the specified message items using the routers underlying services, aborting any pending
+// sends when ctx is done. Services that does not support rich messages are sent the text of the items as a plain
+// message. The returned errors are in the same order as the services were added.
+func (router *ServiceRouter) SendItemsContext(ctx context.Context, items []t.MessageItem, params t.Params) []error {
 	if router == nil {
 		return []error{fmt.Errorf("error sending message: no senders")}
 	}
 
-	// Fallback using old API for now
-	message := strings.Builder{}
-	for _, item := range items {
-		message.WriteString(item.Text)
-	}
-
-	serviceCount := len(router.services)
-	errors := make([]error, serviceCount)
-	results := router.SendAsync(message.String(), &params)
+	return router.sendThrough(ctx, richNotification(items), &params).errors()
+}
 
-	for i := range router.services {
-		errors[i] = <-results
+// SendItemsContextWithResults sends the specified message items using the routers underlying services, aborting any
+// pending sends when ctx is done. The returned results are in the same order as the services were added.
+func (router *ServiceRouter) SendItemsContextWithResults(ctx context.Context, items []t.MessageItem, params t.Params) []SendResult {
+	if router == nil {
+		return []SendResult{{Err: fmt.Errorf("error sending message: no senders")}}
 	}
 
-	return errors
+	return router.sendThrough(ctx, richNotification(items), &params).collect()
 }
 
 // SendAsync sends the specified message using the routers underlying services
 func (router *ServiceRouter) SendAsync(message string, params *t.Params) chan error {
-	serviceCount := len(router.services)
-	proxy := make(chan error, serviceCount)
-	errors := make(chan error, serviceCount)
+	return router.SendAsyncContext(context.Background(), message, params)
+}
 
-	if params == nil {
-		params = &t.Params{}
-	}
-	for _, service := range router.services {
-		go sendToService(service, proxy, router.Timeout, message, *params)
-	}
+// SendAsyncContext sends the specified message using the routers underlying services, aborting any pending sends
+// when ctx is done
+func (router *ServiceRouter) SendAsyncContext(ctx context.Context, message string, params *t.Params) chan error {
+	send := router.sendThrough(ctx, plainNotification(message), params)
+	errors := make(chan error, len(send.services))
 
 	go func() {
-		for i := 0; i < serviceCount; i++ {
-			errors <- <-proxy
+		for range send.services {
+			errors <- (<-send.results).Err
 		}
 		close(errors)
 	}()
@@ -105,43 +275,411 @@ func (router *ServiceRouter) SendAsync(message string, params *t.Params) chan er
 	return errors
 }
 
-func sendToService(service t.Service, results chan error, timeout time.Duration, message string, params t.Params) {
-	result := make(chan error)
+// SendAsyncContextWithResults sends the specified message using the routers underlying services, aborting any
+// pending sends when ctx is done. The results are received as each service completes, and identify the service
+// that they belong to.
+func (router *ServiceRouter) SendAsyncContextWithResults(ctx context.Context, message string, params *t.Params) chan SendResult {
+	send := router.sendThrough(ctx, plainNotification(message), params)
+	results := make(chan SendResult, len(send.services))
+
+	go func() {
+		for range send.services {
+			results <- (<-send.results).SendResult
+		}
+		close(results)
+	}()
+
+	return results
+}
+
+// serviceResult is the outcome of sending using the service at index in the services of the send
+type serviceResult struct {
+	index int
+	SendResult
+}
+
+// pendingSend is a send in progress using a snapshot of the routers services and rules, which receives one result for
+// each of the services
+type pendingSend struct {
+	services []routedService
+	rules    []Rule
+	results  chan serviceResult
+}
+
+// newSend returns a send using the current services and rules of the router
+func (router *ServiceRouter) newSend() *pendingSend {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	return &pendingSend{
+		services: router.services,
+		rules:    router.Rules,
+		results:  make(chan serviceResult, len(router.services)),
+	}
+}
+
+// skip reports the service at index as not used for sending, with the error if it is not nil
+func (send *pendingSend) skip(index int, err error) {
+	result := send.services[index].newResult()
+	result.Err = err
+	send.results <- serviceResult{index, result}
+}
+
+// skipAll reports all the services as not used for sending, with the error if it is not nil
+func (send *pendingSend) skipAll(err error) {
+	for i := range send.services {
+		send.skip(i, err)
+	}
+}
+
+// collect returns the results of the send, in the same order as the services
+func (send *pendingSend) collect() []SendResult {
+	sendResults := make([]SendResult, len(send.services))
+	for range send.services {
+		result := <-send.results
+		sendResults[result.index] = result.SendResult
+	}
+	return sendResults
+}
+
+// errors returns the errors from the results of the send, in the same order as the services
+func (send *pendingSend) errors() []error {
+	return ResultErrors(send.collect())
+}
+
+// sendAll starts sending the notification using the services of the send, unless it is suppressed as a duplicate.
+// Suppressed notifications are reported without errors. Dry runs are never suppressed, nor recorded as sent.
+func (router *ServiceRouter) sendAll(ctx context.Context, send *pendingSend, content notification, params t.Params) {
+	if isDryRun(ctx) {
+		router.deliver(ctx, send, content, params)
+		return
+	}
+
+	suppressed, closeWindow := router.suppressDuplicate(content, params)
+	if suppressed {
+		send.skipAll(nil)
+		return
+	}
+
+	if closeWindow != nil {
+		router.deliverOnce(ctx, send, content, params, closeWindow)
+		return
+	}
+	router.deliver(ctx, send, content, params)
+}
+
+// deliver starts sending the notification using the services selected by the rules of the send according to the
+// routers Strategy, reporting the result of each service as they complete. Services that were not selected are
+// reported first, and services skipped by the strategy last, both without an error.
+func (router *ServiceRouter) deliver(ctx context.Context, send *pendingSend, content notification, params t.Params) {
+	results := send.results
+
+	routed := router.route(send, content, params)
+	isRouted := make([]bool, len(send.services))
+	for _, index := range routed {
+		isRouted[index] = true
+	}
+	for index, rs := range send.services {
+		if !isRouted[index] {
+			result := rs.newResult()
+			result.unrouted = true
+			results <- serviceResult{index, result}
+		}
+	}
+
+	serviceCount := len(routed)
+	required := router.Strategy.required(serviceCount)
+	if required >= serviceCount {
+		for _, index := range routed {
+			router.startSend(ctx, send, index, content, params, results)
+		}
+		return
+	}
+
+	go func() {
+		attempts := make(chan serviceResult, serviceCount)
+		next, running, succeeded := 0, 0, 0
+		for ; next < required; next++ {
+			router.startSend(ctx, send, routed[next], content, params, attempts)
+			running++
+		}
+
+		for running > 0 {
+			result := <-attempts
+			running--
+			results <- result
+
+			if result.Err == nil {
+				succeeded++
+			} else if next < serviceCount && succeeded+running < required {
+				router.log(fmt.Sprintf("Send failed, falling back to service #%d: %v", routed[next]+1, result.Err))
+				router.startSend(ctx, send, routed[next], content, params, attempts)
+				next++
+				running++
+			}
+		}
+
+		for ; next < serviceCount; next++ {
+			send.skip(routed[next], nil)
+		}
+	}()
+}
+
+// startSend starts sending the notification using the service at index in the services of the send, writing the
+// result to results when done
+func (router *ServiceRouter) startSend(ctx context.Context, send *pendingSend, index int, content notification, params t.Params, results chan<- serviceResult) {
+	rs := send.services[index]
+	policy := rs.options.retryPolicy(router.RetryPolicy)
+	hooks := router.Hooks
+	content, params, formatErr := rs.formatContent(content, rs.sendParams(params, content.templated))
+	if isDryRun(ctx) {
+		rs.startDryRun(ctx, index, content, params, formatErr, results)
+		return
+	}
+
+	go func() {
+		result := rs.newResult()
+		ctx, hookContexts := beforeSend(ctx, hooks, SendInfo{Scheme: result.Scheme, URL: result.URL})
+		ctx, messageIDs := t.WithMessageIDs(ctx)
+		start := time.Now()
+
+		attempts, err := 0, formatErr
+		if err == nil {
+			attempts, err = sendToService(ctx, rs.Service, rs.limiter, router.Timeout, policy, content, params)
+		}
+
+		result.Duration = time.Since(start)
+		result.Attempts = attempts
+		result.MessageIDs = messageIDs.IDs()
+		result.Err = rs.redactError(err)
+
+		afterSend(hooks, hookContexts, result)
+		results <- serviceResult{index, result}
+	}()
+}
+
+// sendToService sends the notification using the service according to the retry policy, returning the number of
+// attempts that were made. Digests that are split into multiple messages are sent in order, retrying each separately.
+func sendToService(ctx context.Context, service t.Service, limiter *ratelimit.Limiter, timeout time.Duration, policy retry.Policy, content notification, params t.Params) (int, error) {
+	name := serviceName(service)
 
+	attempts := 0
+	var err error
+	for _, part := range content.parts(service) {
+		if err = sendPart(ctx, service, name, limiter, timeout, policy, part, params, &attempts); err != nil {
+			break
+		}
+	}
+
+	if err != nil && ctx.Err() != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = timeoutError{name}
+		} else {
+			err = fmt.Errorf("failed to send using %v: %w", name, ctx.Err())
+		}
+	}
+
+	return attempts, err
+}
+
+// sendPart sends a single part of the notification according to the retry policy, adding the attempts to attempts
+func sendPart(ctx context.Context, service t.Service, name string, limiter *ratelimit.Limiter, timeout time.Duration, policy retry.Policy, content notification, params t.Params, attempts *int) error {
+	return policy.Do(ctx, func(ctx context.Context) error {
+		*attempts++
+
+		// Every attempt counts against the rate limit, and waiting for it is not part of the attempt timeout
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		attemptCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		err := content.sendUsing(attemptCtx, service, params)
+		if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return timeoutError{name}
+		}
+		return err
+	})
+}
+
+// serviceName returns the name of the package that implements the service
+func serviceName(service t.Service) string {
 	// TODO: There really ought to be a better way to name the services
 	pkg := reflect.TypeOf(service).Elem().PkgPath()
-	serviceName := pkg[strings.LastIndex(pkg, "/")+1:]
+	return pkg[strings.LastIndex(pkg, "/")+1:]
+}
 
-	go func() { result <- service.Send(message, &params) }()
+// timeoutError is returned when a service did not finish sending before the timeout was reached
+type timeoutError struct {
+	serviceName string
+}
 
-	select {
-	case res := <-result:
-		results <- res
-	case <-time.After(timeout):
-		results <- fmt.Errorf("failed to send using %v: timed out", serviceName)
-	}
+func (te timeoutError) Error() string {
+	return fmt.Sprintf("failed to send using %v: timed out", te.serviceName)
 }
 
-// Enqueue adds the message to an internal queue and sends it when Flush is invoked
+// Unwrap returns context.DeadlineExceeded, allowing the error to be identified as a timeout
+func (te timeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Enqueue adds the message to an internal queue and sends it when Flush is invoked, or by RunDigest. It is safe to
+// call from multiple goroutines.
 func (router *ServiceRouter) Enqueue(message string, v ...interface{}) {
 	if len(v) > 0 {
 		message = fmt.Sprintf(message, v...)
 	}
+
+	router.queueLock.Lock()
+	defer router.queueLock.Unlock()
 	router.queue = append(router.queue, message)
+	if router.queueFull != nil && router.queueMaxItems > 0 && len(router.queue) >= router.queueMaxItems {
+		select {
+		case router.queueFull <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // Flush sends all messages that have been queued up as a combined message. This method should be deferred!
 func (router *ServiceRouter) Flush(params *t.Params) {
-	// Since this method is supposed to be deferred we just have to ignore errors
-	_ = router.Send(strings.Join(router.queue, "\n"), params)
+	router.FlushContext(context.Background(), params)
+}
+
+// FlushContext sends all messages that have been queued up as a combined message, aborting any pending sends when
+// ctx is done. Services that declare a MessageLimit are sent multiple messages if it does not fit in a single one.
+// Nothing is sent if the queue is empty.
+func (router *ServiceRouter) FlushContext(ctx context.Context, params *t.Params) {
+	router.queueLock.Lock()
+	queue := router.queue
 	router.queue = []string{}
+	router.queueLock.Unlock()
+
+	if len(queue) < 1 {
+		return
+	}
+
+	// Since this method is supposed to be deferred we just have to ignore errors
+	_ = router.sendThrough(ctx, digestNotification(queue), params).errors()
+}
+
+// ServiceURLs returns the URLs of the routers services, in the same order as the errors returned by Send
+func (router *ServiceRouter) ServiceURLs() []string {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+
+	urls := make([]string, len(router.services))
+	for i, rs := range router.services {
+		urls[i] = rs.url
+	}
+	return urls
+}
+
+// RedactedServiceURLs returns the URLs of the routers services with their secret values redacted, unless ShowSecrets
+// was set when they were added
+func (router *ServiceRouter) RedactedServiceURLs() []string {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+
+	urls := make([]string, len(router.services))
+	for i, rs := range router.services {
+		urls[i] = rs.redactedURL()
+	}
+	return urls
+}
+
+// Verify checks the credentials of the routers services that implement types.Verifier, returning an error for each of
+// the services, in the same order as ServiceURLs. The error is nil for the services that could be verified, as well as
+// for the services that cannot verify their credentials without sending a message.
+func (router *ServiceRouter) Verify(ctx context.Context) []error {
+	router.lock.RLock()
+	services := router.services
+	router.lock.RUnlock()
+
+	errs := make([]error, len(services))
+	for i, rs := range services {
+		if verifier, ok := rs.Service.(t.Verifier); ok {
+			if err := verifier.Verify(ctx); err != nil {
+				errs[i] = fmt.Errorf("failed to verify %s: %w", rs.redactedURL(), err)
+			}
+		}
+	}
+	return errs
+}
+
+// RedactURL returns the service URL with the secret config values of its service redacted, without initializing the
+// service. If the secret values cannot be determined, everything but the scheme of the URL is redacted.
+func RedactURL(rawURL string) string {
+	scheme, configURL, err := (&ServiceRouter{}).ExtractServiceName(rawURL)
+	if err != nil {
+		return redact.Placeholder
+	}
+	redacted := scheme + "://" + redact.Placeholder
+
+	service, err := newService(scheme)
+	if err != nil {
+		return redacted
+	}
+	if _, err := extractServiceOptions(configURL); err != nil {
+		return redacted
+	}
+	if configURL.Scheme != scheme {
+		customURLService, ok := service.(t.CustomURLService)
+		if !ok {
+			return redacted
+		}
+		if configURL, err = customURLService.GetConfigURLFromCustom(configURL); err != nil {
+			return redacted
+		}
+	}
+
+	config := format.GetServiceConfig(service)
+	if config == nil || config.SetURL(configURL) != nil {
+		return redacted
+	}
+	redactor := redact.New(format.GetSecretValues(config)...)
+	return redactor.URL(rawURL)
 }
 
 // SetLogger sets the logger that the services will use to write progress logs
 func (router *ServiceRouter) SetLogger(logger t.StdLogger) {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+
 	router.logger = logger
-	for _, service := range router.services {
-		service.SetLogger(logger)
+	for _, rs := range router.services {
+		rs.SetLogger(rs.redactLogger(logger))
+	}
+}
+
+// SetHTTPClient sets the client that the services will use for their HTTP requests, including services that are added
+// later. A nil client makes the services use http.DefaultClient. Configs with HTTP options override the client, while
+// configs without them that are loaded using ReplaceFromConfig restore it.
+func (router *ServiceRouter) SetHTTPClient(client *http.Client) {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+
+	router.baseClient = client
+	router.useHTTPClient(client)
+}
+
+// useHTTPClient sets the client used by the current services and the ones that are added later. It must be called with
+// the lock held.
+func (router *ServiceRouter) useHTTPClient(client *http.Client) {
+	router.httpClient = client
+	for _, rs := range router.services {
+		setHTTPClient(rs.Service, client)
+	}
+}
+
+// setHTTPClient sets the client used for HTTP requests by the service, if it makes any
+func setHTTPClient(service t.Service, client *http.Client) {
+	if hcs, ok := service.(t.HTTPClientService); ok {
+		hcs.SetHTTPClient(client)
 	}
 }
 
@@ -164,46 +702,108 @@ func (router *ServiceRouter) ExtractServiceName(rawURL string) (string, *url.URL
 
 // Route a message to a specific notification service using the notification URL
 func (router *ServiceRouter) Route(rawURL string, message string) error {
+	return router.RouteContext(context.Background(), rawURL, message)
+}
 
-	service, err := router.Locate(rawURL)
+// RouteContext routes a message to a specific notification service using the notification URL, aborting the send
+// when ctx is done. The router options in the URL, such as retries, ratelimit and format, are applied to the send.
+func (router *ServiceRouter) RouteContext(ctx context.Context, rawURL string, message string) error {
+	rs, err := router.initRoutedService(rawURL)
 	if err != nil {
 		return err
 	}
 
-	return service.Send(message, nil)
+	router.lock.RLock()
+	rs.limiter = rs.limiterFrom(router.limiters)
+	router.lock.RUnlock()
+
+	send := &pendingSend{services: []routedService{rs}, results: make(chan serviceResult, 1)}
+	router.startSend(ctx, send, 0, plainNotification(message), t.Params{}, send.results)
+	return (<-send.results).Err
 }
 
 func (router *ServiceRouter) initService(rawURL string) (t.Service, error) {
+	rs, err := router.initRoutedService(rawURL)
+	return rs.Service, err
+}
+
+func (router *ServiceRouter) initRoutedService(rawURL string) (routedService, error) {
+	resolvedURL, secrets, err := resolveSecrets(rawURL)
+	if err != nil {
+		return routedService{}, err
+	}
+
+	redactor := &redact.Redactor{}
+	if !router.ShowSecrets {
+		redactor.Add(secrets...)
+	}
+
+	rs, err := router.initResolvedService(resolvedURL, redactor)
+	rs.url = rawURL
+	return rs, redactor.Error(err)
+}
+
+func (router *ServiceRouter) initResolvedService(rawURL string, redactor *redact.Redactor) (routedService, error) {
+	rs := routedService{redactor: redactor, showSecrets: router.ShowSecrets}
+
+	router.lock.RLock()
+	logger, httpClient := router.logger, router.httpClient
+	router.lock.RUnlock()
 
 	scheme, configURL, err := router.ExtractServiceName(rawURL)
 	if err != nil {
-		return nil, err
+		return rs, err
 	}
 
 	service, err := newService(scheme)
 	if err != nil {
-		return nil, err
+		return rs, err
+	}
+
+	rs.options, err = extractServiceOptions(configURL)
+	if err != nil {
+		return rs, err
 	}
 
+	var customURL string
 	if configURL.Scheme != scheme {
-		router.log("Got custom URL:", configURL.String())
+		customURL = configURL.String()
 		customURLService, ok := service.(t.CustomURLService)
 		if !ok {
-			return nil, fmt.Errorf("custom URLs are not supported by '%s' service", scheme)
+			router.log("Got custom URL:", redactor.URL(customURL))
+			return rs, fmt.Errorf("custom URLs are not supported by '%s' service", scheme)
 		}
 		configURL, err = customURLService.GetConfigURLFromCustom(configURL)
 		if err != nil {
-			return nil, err
+			router.log("Got custom URL:", redactor.URL(customURL))
+			return rs, err
 		}
-		router.log("Converted service URL:", configURL.String())
 	}
 
-	err = service.Initialize(configURL, router.logger)
-	if err != nil {
-		return service, err
+	rs.Service = service
+	rs.scheme = scheme
+	rs.limit = serviceRateLimit(scheme, rs.options.rateLimit)
+	rs.limiterKey = limiterKey(rs.limit, configURL)
+	if httpClient != nil {
+		setHTTPClient(service, httpClient)
+	}
+	err = service.Initialize(configURL, redactor.Logger(logger))
+
+	if err == nil {
+		rs.ignoredKeys = unsupportedKeys(service, routingKeys)
+	}
+
+	if err == nil && !router.ShowSecrets {
+		// The secret config values are only known once the service has been initialized
+		redactor.Add(format.GetSecretValues(format.GetServiceConfig(service))...)
 	}
 
-	return service, nil
+	if customURL != "" {
+		router.log("Got custom URL:", redactor.URL(customURL))
+		router.log("Converted service URL:", redactor.URL(configURL.String()))
+	}
+
+	return rs, err
 }
 
 // NewService returns a new uninitialized service instance
@@ -213,35 +813,32 @@ func (*ServiceRouter) NewService(serviceScheme string) (t.Service, error) {
 
 // newService returns a new uninitialized service instance
 func newService(serviceScheme string) (t.Service, error) {
-	serviceFactory, valid := serviceMap[strings.ToLower(serviceScheme)]
+	serviceFactory, valid := lookupServiceFactory(serviceScheme)
 	if !valid {
 		return nil, fmt.Errorf("unknown service %q", serviceScheme)
 	}
 	return serviceFactory(), nil
 }
 
-// ListServices returns the available services
+// ListServices returns the available services, including the ones added using Register
 func (router *ServiceRouter) ListServices() []string {
-	services := make([]string, len(serviceMap))
-
-	i := 0
-	for key := range serviceMap {
-		services[i] = key
-		i++
-	}
-
-	return services
+	return listServiceSchemes()
 }
 
-// Locate returns the service implementation that corresponds to the given service URL
+// Locate returns the service implementation that corresponds to the given service URL. The router options in the URL,
+// such as retries, ratelimit and format, are only applied when sending through the router, e.g. using RouteContext.
 func (router *ServiceRouter) Locate(rawURL string) (t.Service, error) {
 	service, err := router.initService(rawURL)
 	return service, err
 }
 
 func (router *ServiceRouter) log(v ...interface{}) {
-	if router.logger == nil {
+	router.lock.RLock()
+	logger := router.logger
+	router.lock.RUnlock()
+
+	if logger == nil {
 		return
 	}
-	router.logger.Println(v...)
+	logger.Println(v...)
 }
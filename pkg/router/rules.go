@@ -0,0 +1,124 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+const (
+	// LevelKey is the param used for setting the level of plain messages, which is matched by routing rules
+	LevelKey = "level"
+	// TagsKey is the param containing the comma separated tags of a message, which are matched by routing rules
+	TagsKey = "tags"
+)
+
+// routingKeys are the params that are only passed on to services that have a config key with the same name
+var routingKeys = []string{LevelKey, TagsKey}
+
+// Rule selects the services that receive the messages it matches. A message has to match all the conditions that are
+// set, and a rule without any conditions matches all messages.
+type Rule struct {
+	// Levels matches messages with any of the levels. For message items, the highest level of the items is used.
+	Levels []t.MessageLevel
+	// Tags matches messages with any of the tags in their tags param
+	Tags []string
+	// Pattern matches messages where either the title param or the text matches the regular expression
+	Pattern *regexp.Regexp
+	// Services are the names of the services that receive the matched messages. Services that were added without a
+	// name, e.g. using AddService, are matched by the scheme of their URL instead.
+	Services []string
+}
+
+// matches returns whether the notification and its params satisfies all the conditions of the rule
+func (rule Rule) matches(content notification, params t.Params) bool {
+	if len(rule.Levels) > 0 && !containsLevel(rule.Levels, content.level(params)) {
+		return false
+	}
+
+	if len(rule.Tags) > 0 && !containsAny(rule.Tags, splitTags(params[TagsKey])) {
+		return false
+	}
+
+	if rule.Pattern != nil && !rule.Pattern.MatchString(params["title"]) && !rule.Pattern.MatchString(content.text()) {
+		return false
+	}
+
+	return true
+}
+
+// route returns the indices of the services that should receive the notification according to the rules of the send.
+// Without any rules, all services are used.
+func (router *ServiceRouter) route(send *pendingSend, content notification, params t.Params) []int {
+	if len(send.rules) < 1 {
+		indices := make([]int, len(send.services))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	names := map[string]bool{}
+	for _, rule := range send.rules {
+		if !rule.matches(content, params) {
+			continue
+		}
+		for _, name := range rule.Services {
+			names[name] = true
+		}
+	}
+
+	var indices []int
+	for i, rs := range send.services {
+		if names[rs.ruleName()] {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) < 1 {
+		router.log("No routing rule matched message:", util.Ellipsis(content.text(), 100))
+	}
+	return indices
+}
+
+// ruleName returns the name that routing rules use for selecting the service, which is the scheme of its URL if it
+// was added without a name
+func (rs routedService) ruleName() string {
+	if rs.name == "" {
+		return rs.scheme
+	}
+	return rs.name
+}
+
+// splitTags returns the non-empty tags in the comma separated list
+func splitTags(tags string) []string {
+	var split []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			split = append(split, tag)
+		}
+	}
+	return split
+}
+
+func containsLevel(levels []t.MessageLevel, level t.MessageLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package router
+
+import (
+	"regexp"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testRulesYAML = `
+targets:
+  logs:
+    url: strategy-test://logs
+  chat:
+    url: strategy-test://chat
+  pager:
+    url: strategy-test://pager
+  mail:
+    url: strategy-test://mail
+groups:
+  oncall: [pager, mail]
+  default: [logs, chat, oncall]
+rules:
+  - levels: [debug]
+    targets: [logs]
+  - levels: [warning]
+    targets: [chat]
+  - levels: [Error]
+    targets: [oncall]
+  - tags: [db]
+    pattern: (?i)replication
+    targets: [pager]
+`
+
+// newRulesRouter returns a router with a named service for each host, using the host as the name
+func newRulesRouter(rules []Rule, hosts ...string) *ServiceRouter {
+	router, err := New(nil)
+	Expect(err).NotTo(HaveOccurred())
+	router.Rules = rules
+	for _, host := range hosts {
+		Expect(router.AddNamedService(host, "strategy-test://"+host)).To(Succeed())
+	}
+	return router
+}
+
+var _ = Describe("the routing rules", func() {
+	BeforeEach(resetSends)
+
+	levelRules := []Rule{
+		{Levels: []t.MessageLevel{t.Debug}, Services: []string{"logs"}},
+		{Levels: []t.MessageLevel{t.Warning}, Services: []string{"chat"}},
+		{Levels: []t.MessageLevel{t.Error}, Services: []string{"pager", "mail"}},
+	}
+
+	It("should use all services if there are no rules", func() {
+		router := newRulesRouter(nil, "logs", "chat")
+		Expect(router.Send("message", nil)).To(Equal([]error{nil, nil}))
+		Expect(sentHosts()).To(ConsistOf("logs", "chat"))
+	})
+
+	It("should select the services using the level param of plain messages", func() {
+		router := newRulesRouter(levelRules, "logs", "chat", "pager", "mail")
+		router.Send("message", &t.Params{LevelKey: "error"})
+		Expect(sentHosts()).To(ConsistOf("pager", "mail"))
+
+		resetSends()
+		router.Send("message", &t.Params{LevelKey: "debug"})
+		Expect(sentHosts()).To(ConsistOf("logs"))
+	})
+
+	It("should use the highest level of message items", func() {
+		router := newRulesRouter(levelRules, "logs", "chat", "pager", "mail")
+		router.SendItems([]t.MessageItem{{Text: "a", Level: t.Info}, {Text: "b", Level: t.Warning}}, nil)
+		Expect(sentHosts()).To(ConsistOf("chat"))
+	})
+
+	It("should match any of the tags in the tags param", func() {
+		router := newRulesRouter([]Rule{{Tags: []string{"db"}, Services: []string{"pager"}}}, "chat", "pager")
+		router.Send("message", &t.Params{TagsKey: "prod, db"})
+		Expect(sentHosts()).To(ConsistOf("pager"))
+
+		resetSends()
+		router.Send("message", &t.Params{TagsKey: "prod"})
+		Expect(sentHosts()).To(BeEmpty())
+	})
+
+	It("should match the pattern against the title or the text", func() {
+		rules := []Rule{{Pattern: regexp.MustCompile(`(?i)disk`), Services: []string{"chat"}}}
+		router := newRulesRouter(rules, "chat")
+		router.Send("Disk full", nil)
+		router.Send("message", &t.Params{"title": "disk warning"})
+		router.Send("message", nil)
+		Expect(sentHosts()).To(Equal([]string{"chat", "chat"}))
+	})
+
+	It("should send to the services of all the matching rules once", func() {
+		rules := []Rule{{Services: []string{"chat"}}, {Levels: []t.MessageLevel{t.Error}, Services: []string{"chat", "pager"}}}
+		router := newRulesRouter(rules, "chat", "pager")
+		router.Send("message", &t.Params{LevelKey: "error"})
+		Expect(sentHosts()).To(ConsistOf("chat", "pager"))
+	})
+
+	It("should select the services without a name using their scheme", func() {
+		router, err := New(nil, "strategy-test://unnamed", "result-test://a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(router.AddNamedService("result-test", "strategy-test://named")).To(Succeed())
+		router.Rules = []Rule{{Services: []string{"strategy-test"}}}
+
+		results := router.SendWithResults("message", nil)
+		Expect(sentHosts()).To(ConsistOf("unnamed"))
+		Expect(results[1].Skipped()).To(BeTrue())
+		Expect(results[2].Skipped()).To(BeTrue())
+	})
+
+	It("should report the services that were not selected as skipped", func() {
+		router := newRulesRouter(levelRules, "logs", "chat")
+		results := router.SendWithResults("message", &t.Params{LevelKey: "warning"})
+		Expect(results[0].Skipped()).To(BeTrue())
+		Expect(results[1].Skipped()).To(BeFalse())
+	})
+
+	It("should apply the strategy to the selected services", func() {
+		rules := []Rule{{Services: []string{"fail", "b"}}}
+		router := newRulesRouter(rules, "a", "fail", "b")
+		router.Strategy = Failover
+
+		results := router.SendWithResults("message", nil)
+		Expect(sentHosts()).To(Equal([]string{"fail", "b"}))
+		Expect(router.DeliveredResults(results)).To(BeTrue())
+
+		resetSends()
+		router.Rules = []Rule{{Services: []string{"fail"}}}
+		results = router.SendWithResults("message", nil)
+		Expect(router.Delivered(ResultErrors(results))).To(BeTrue())
+		Expect(router.DeliveredResults(results)).To(BeFalse())
+	})
+
+	It("should only pass the routing params to services that supports them", func() {
+		router, err := New(nil, "teams://11111111-4444-4444-8444-cccccccccccc@22222222-4444-4444-8444-cccccccccccc/33333333012222222222333333333344/44444444-4444-4444-8444-cccccccccccc", "ntfy://ntfy.sh/topic")
+		Expect(err).NotTo(HaveOccurred())
+
+		params := t.Params{LevelKey: "error", TagsKey: "db", "title": "title"}
+		Expect(router.services[0].sendParams(params, false)).To(Equal(t.Params{"title": "title"}))
+		Expect(router.services[1].sendParams(params, false)).To(Equal(t.Params{TagsKey: "db", "title": "title"}))
+	})
+
+	When("loading rules from a config", func() {
+		It("should select the services using the target names", func() {
+			config, err := ParseConfig([]byte(testRulesYAML), YAMLFormat)
+			Expect(err).NotTo(HaveOccurred())
+
+			router, err := NewFromConfig(nil, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(router.Rules).To(HaveLen(4))
+			Expect(router.Rules[2].Services).To(Equal([]string{"pager", "mail"}))
+
+			router.Send("message", &t.Params{LevelKey: "error"})
+			Expect(sentHosts()).To(ConsistOf("pager", "mail"))
+
+			resetSends()
+			router.Send("Replication lag", &t.Params{TagsKey: "db"})
+			Expect(sentHosts()).To(ConsistOf("pager"))
+		})
+
+		It("should return an error for invalid rules", func() {
+			for _, rule := range []string{
+				"{levels: [critical], targets: [logs]}",
+				"{pattern: '(', targets: [logs]}",
+				"{targets: [missing]}",
+				"{levels: [debug]}",
+			} {
+				_, err := ParseConfig([]byte("targets: {logs: {url: 'logger://'}}\nrules: ["+rule+"]\n"), YAMLFormat)
+				Expect(err).To(HaveOccurred(), rule)
+			}
+		})
+	})
+})
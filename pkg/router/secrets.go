@@ -0,0 +1,120 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// secretRefPattern matches secret references in the form ${env:NAME} or ${file:/path/to/secret}
+var secretRefPattern = regexp.MustCompile(`\$\{(env|file):([^}]+)}`)
+
+// resolveSecrets replaces the secret references in the raw service URL with their values, escaped for the part of the
+// URL that they are in, returning the resolved URL together with the values that were inserted
+func resolveSecrets(rawURL string) (string, []string, error) {
+	matches := secretRefPattern.FindAllStringSubmatchIndex(rawURL, -1)
+	if len(matches) < 1 {
+		return rawURL, nil, nil
+	}
+
+	// The parts are found without the references, since they may contain delimiters, like the slashes of file paths
+	parts := findURLParts(secretRefPattern.ReplaceAllString(rawURL, ""))
+
+	var secrets []string
+	resolved := strings.Builder{}
+	last, removed := 0, 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		value, err := lookupSecret(rawURL[match[2]:match[3]], rawURL[match[4]:match[5]])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve secret reference %v: %w", rawURL[start:end], err)
+		}
+		escaped := parts.escape(start-removed, value)
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+		if escaped != value {
+			// The escaped value is redacted as well, since it is not always one of the encoded forms of the redactor
+			secrets = append(secrets, escaped)
+		}
+
+		resolved.WriteString(rawURL[last:start])
+		resolved.WriteString(escaped)
+		last = end
+		removed += end - start
+	}
+	resolved.WriteString(rawURL[last:])
+
+	return resolved.String(), secrets, nil
+}
+
+// urlParts contains the offsets of the delimiters between the parts of a raw URL
+type urlParts struct {
+	authority int
+	at        int
+	path      int
+	query     int
+	fragment  int
+}
+
+// findURLParts returns the offsets of the parts of the raw URL, without validating it
+func findURLParts(rawURL string) urlParts {
+	parts := urlParts{at: -1}
+	if index := strings.Index(rawURL, "://"); index >= 0 {
+		parts.authority = index + 3
+	}
+
+	parts.path = indexFrom(rawURL, parts.authority, "/?#")
+	if index := strings.LastIndex(rawURL[parts.authority:parts.path], "@"); index >= 0 {
+		parts.at = parts.authority + index
+	}
+	parts.query = indexFrom(rawURL, parts.path, "?#")
+	parts.fragment = indexFrom(rawURL, parts.query, "#")
+	return parts
+}
+
+// indexFrom returns the index of the first of the chars in s, starting at from, or the length of s if there are none
+func indexFrom(s string, from int, chars string) int {
+	if index := strings.IndexAny(s[from:], chars); index >= 0 {
+		return from + index
+	}
+	return len(s)
+}
+
+// escape returns the value escaped for the part of the URL that it is inserted into at the offset
+func (parts urlParts) escape(offset int, value string) string {
+	switch {
+	case offset < parts.authority:
+		return value
+	case offset <= parts.at:
+		// Colons are kept, so that a single reference can contain both the username and the password
+		return strings.ReplaceAll(url.User(value).String(), "%3A", ":")
+	case offset <= parts.query:
+		// Hosts are escaped as paths as well, since they do not allow any delimiters
+		return url.PathEscape(value)
+	case offset <= parts.fragment:
+		return url.QueryEscape(value)
+	}
+	return url.PathEscape(value)
+}
+
+func lookupSecret(source string, name string) (string, error) {
+	switch source {
+	case "env":
+		value, found := os.LookupEnv(name)
+		if !found {
+			return "", fmt.Errorf("environment variable %v is not set", name)
+		}
+		return value, nil
+	case "file":
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		// Secret files usually end with a newline, which is never part of the secret
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", fmt.Errorf("unknown secret source %q", source)
+}
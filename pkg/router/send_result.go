@@ -0,0 +1,69 @@
+package router
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SendResult is the outcome of sending a message using one of the routers services
+type SendResult struct {
+	// Scheme is the scheme of the service URL, identifying the service
+	Scheme string
+	// URL is the service URL, with its secrets redacted unless ShowSecrets was set when the service was added
+	URL string
+	// Duration is the time spent sending, including retries and waiting for the rate limit
+	Duration time.Duration
+	// Attempts is the number of times sending was attempted, which is zero if the service was skipped
+	Attempts int
+	// MessageIDs are the IDs that the provider assigned to the sent messages, for services that report them
+	MessageIDs []string
+	// Err is the reason that sending failed, or nil if it succeeded or was skipped
+	Err error
+	// Requests are the requests that the service would have made, for dry runs
+	Requests []Request
+	// unrouted is set if the service was skipped since it was not selected by the routers Rules
+	unrouted bool
+	// dryRun is set if the result is from a dry run, where no attempts are made
+	dryRun bool
+}
+
+// Skipped returns whether the service was not used for sending, either due to the routers Rules or Strategy, or
+// because the message was suppressed as a duplicate
+func (result SendResult) Skipped() bool {
+	return result.Attempts == 0 && result.Err == nil && !result.dryRun
+}
+
+// MarshalJSON returns the result as a JSON object, with the duration in milliseconds and the error as a string
+func (result SendResult) MarshalJSON() ([]byte, error) {
+	jsonResult := struct {
+		Scheme     string    `json:"scheme"`
+		URL        string    `json:"url"`
+		DurationMS int64     `json:"durationMs"`
+		Attempts   int       `json:"attempts"`
+		Skipped    bool      `json:"skipped"`
+		MessageIDs []string  `json:"messageIds,omitempty"`
+		Error      string    `json:"error,omitempty"`
+		Requests   []Request `json:"requests,omitempty"`
+	}{
+		Scheme:     result.Scheme,
+		URL:        result.URL,
+		DurationMS: result.Duration.Milliseconds(),
+		Attempts:   result.Attempts,
+		Skipped:    result.Skipped(),
+		MessageIDs: result.MessageIDs,
+		Requests:   result.Requests,
+	}
+	if result.Err != nil {
+		jsonResult.Error = result.Err.Error()
+	}
+	return json.Marshal(jsonResult)
+}
+
+// ResultErrors returns the errors of the results, in the same order, which can be passed to Delivered
+func ResultErrors(results []SendResult) []error {
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return errs
+}
@@ -0,0 +1,143 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// resultTestService reports the host of its URL as the message ID. If the host is "flaky", the first attempt fails
+// with a transient error, and if it is "fail" all attempts fail.
+type resultTestService struct {
+	standard.Standard
+	host  string
+	calls int
+}
+
+func (service *resultTestService) Initialize(serviceURL *url.URL, _ t.StdLogger) error {
+	service.host = serviceURL.Host
+	return nil
+}
+
+func (service *resultTestService) Send(message string, params *t.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+func (service *resultTestService) SendContext(ctx context.Context, _ string, _ *t.Params) error {
+	service.calls++
+	if service.host == "fail" {
+		return errors.New("send failed")
+	}
+	if service.host == "flaky" && service.calls == 1 {
+		return fmt.Errorf("flaky: %w", context.DeadlineExceeded)
+	}
+	t.ReportMessageID(ctx, "id-"+service.host)
+	return nil
+}
+
+var _ = Describe("the send results", func() {
+	It("should identify the services in the order they were added", func() {
+		router, err := New(nil, "result-test://a", "result-test://fail")
+		Expect(err).NotTo(HaveOccurred())
+
+		results := router.SendWithResults("message", nil)
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Scheme).To(Equal("result-test"))
+		Expect(results[0].URL).To(Equal("result-test://a"))
+		Expect(results[0].Attempts).To(Equal(1))
+		Expect(results[0].MessageIDs).To(Equal([]string{"id-a"}))
+		Expect(results[0].Err).NotTo(HaveOccurred())
+
+		Expect(results[1].URL).To(Equal("result-test://fail"))
+		Expect(results[1].MessageIDs).To(BeEmpty())
+		Expect(results[1].Err).To(MatchError("send failed"))
+
+		Expect(router.Delivered(ResultErrors(results))).To(BeFalse())
+	})
+
+	It("should count the attempts made when retrying", func() {
+		router, err := New(nil, "result-test://flaky?retries=2&backoff=1ms")
+		Expect(err).NotTo(HaveOccurred())
+
+		results := router.SendWithResults("message", nil)
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[0].Attempts).To(Equal(2))
+		Expect(results[0].Duration).To(BeNumerically(">", 0))
+	})
+
+	It("should redact the secrets of the service URLs", func() {
+		router, err := New(nil, "result-test://a?token=s3cr3t")
+		Expect(err).NotTo(HaveOccurred())
+		router.services[0].redactor.Add("s3cr3t")
+
+		results := router.SendWithResults("message", nil)
+		Expect(results[0].URL).NotTo(ContainSubstring("s3cr3t"))
+	})
+
+	It("should report the services skipped by the strategy", func() {
+		router, err := New(nil, "result-test://a", "result-test://b")
+		Expect(err).NotTo(HaveOccurred())
+		router.Strategy = Failover
+
+		results := router.SendWithResults("message", nil)
+		Expect(results[0].Skipped()).To(BeFalse())
+		Expect(results[1].Skipped()).To(BeTrue())
+		Expect(results[1].URL).To(Equal("result-test://b"))
+	})
+
+	It("should identify the services when sending asynchronously", func() {
+		router, err := New(nil, "result-test://a", "result-test://fail")
+		Expect(err).NotTo(HaveOccurred())
+
+		failed := map[string]bool{}
+		for result := range router.SendAsyncContextWithResults(context.Background(), "message", nil) {
+			failed[result.URL] = result.Err != nil
+		}
+		Expect(failed).To(Equal(map[string]bool{"result-test://a": false, "result-test://fail": true}))
+	})
+
+	It("should return the results of sending message items", func() {
+		router, err := New(nil, "result-test://a")
+		Expect(err).NotTo(HaveOccurred())
+
+		results := router.SendItemsContextWithResults(context.Background(), []t.MessageItem{{Text: "item"}}, nil)
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[0].Attempts).To(Equal(1))
+	})
+
+	It("should be marshalled to JSON with the error as a string", func() {
+		result := SendResult{
+			Scheme:     "result-test",
+			URL:        "result-test://a",
+			Duration:   1500 * time.Millisecond,
+			Attempts:   2,
+			MessageIDs: []string{"id-a"},
+			Err:        errors.New("send failed"),
+		}
+		data, err := json.Marshal(result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"scheme": "result-test",
+			"url": "result-test://a",
+			"durationMs": 1500,
+			"attempts": 2,
+			"skipped": false,
+			"messageIds": ["id-a"],
+			"error": "send failed"
+		}`))
+	})
+})
+
+func init() {
+	MustRegister("result-test", func() t.Service { return &resultTestService{} })
+}
@@ -0,0 +1,219 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/format"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/ratelimit"
+	"github.com/containrrr/shoutrrr/pkg/util/redact"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+)
+
+const (
+	// RetriesKey is the service URL query key used to override the number of retries for that service
+	RetriesKey = "retries"
+	// BackoffKey is the service URL query key used to override the initial retry backoff for that service
+	BackoffKey = "backoff"
+	// RateLimitKey is the service URL query key used to override the rate limit for that service, e.g. 20/m
+	RateLimitKey = "ratelimit"
+)
+
+// routedService is a service together with the URL it was created from, the router options supplied in it, the
+// default params used for every send, the redactor for the secrets resolved in the URL and the rate limiter
+type routedService struct {
+	t.Service
+	name     string
+	scheme   string
+	url      string
+	options  serviceOptions
+	params   t.Params
+	redactor *redact.Redactor
+	limiter  *ratelimit.Limiter
+	// limit is the rate limit of the service, and limiterKey identifies the limiter it shares with other services
+	limit      ratelimit.Limit
+	limiterKey string
+	// showSecrets is set if ShowSecrets was set when the service was added, disabling the redaction of dry runs
+	showSecrets bool
+	// ignoredKeys are the routing params that are not config keys of the service, and thus not passed on to it
+	ignoredKeys []string
+}
+
+// redactedURL returns the service URL with any secrets redacted
+func (rs routedService) redactedURL() string {
+	if rs.redactor == nil {
+		return rs.url
+	}
+	return rs.redactor.URL(rs.url)
+}
+
+// newResult returns a result identifying the service, without any outcome
+func (rs routedService) newResult() SendResult {
+	return SendResult{Scheme: rs.scheme, URL: rs.redactedURL()}
+}
+
+// redactError returns the error with any secrets redacted from its message
+func (rs routedService) redactError(err error) error {
+	if rs.redactor == nil {
+		return err
+	}
+	return rs.redactor.Error(err)
+}
+
+// redactLogger returns a logger that redacts the secrets of the service
+func (rs routedService) redactLogger(logger t.StdLogger) t.StdLogger {
+	if rs.redactor == nil {
+		return logger
+	}
+	return rs.redactor.Logger(logger)
+}
+
+// sendParams returns the params for a send, with any default params of the service that were not overridden, and
+// without the routing params that the service does not support. For templated notifications, where the params may only
+// be intended for the template, none of the params that the service does not support are passed on.
+func (rs routedService) sendParams(params t.Params, templated bool) t.Params {
+	if templated {
+		params = rs.supportedParams(params)
+	}
+
+	if len(rs.params) < 1 && !rs.hasIgnoredKeys(params) {
+		return params
+	}
+
+	merged := make(t.Params, len(rs.params)+len(params))
+	for key, value := range rs.params {
+		merged[key] = value
+	}
+	for key, value := range params {
+		merged[key] = value
+	}
+	for _, key := range rs.ignoredKeys {
+		delete(merged, key)
+	}
+	return merged
+}
+
+// supportedParams returns the params that are config keys of the service. Services without a config are assumed to
+// support any params.
+func (rs routedService) supportedParams(params t.Params) t.Params {
+	if format.GetServiceConfig(rs.Service) == nil {
+		return params
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		// The format param is used by the router itself, and is removed by formatContent
+		if key != FormatKey {
+			keys = append(keys, key)
+		}
+	}
+	unsupported := unsupportedKeys(rs.Service, keys)
+	if len(unsupported) < 1 {
+		return params
+	}
+
+	supported := make(t.Params, len(params))
+	for key, value := range params {
+		supported[key] = value
+	}
+	for _, key := range unsupported {
+		delete(supported, key)
+	}
+	return supported
+}
+
+func (rs routedService) hasIgnoredKeys(params t.Params) bool {
+	for _, key := range rs.ignoredKeys {
+		if _, found := params[key]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedKeys returns the keys that are not config keys of the service
+func unsupportedKeys(service t.Service, keys []string) []string {
+	supported := map[string]bool{}
+	if config := format.GetServiceConfig(service); config != nil {
+		pkr := format.NewPropKeyResolver(config)
+		for _, key := range pkr.QueryFields() {
+			supported[key] = true
+		}
+	}
+
+	var unsupported []string
+	for _, key := range keys {
+		if !supported[key] {
+			unsupported = append(unsupported, key)
+		}
+	}
+	return unsupported
+}
+
+// serviceOptions are the router options that can be overridden per service using the service URL query
+type serviceOptions struct {
+	retries   *int
+	backoff   *time.Duration
+	rateLimit *ratelimit.Limit
+	format    string
+}
+
+// retryPolicy returns the base policy with any overrides from the service options applied
+func (so serviceOptions) retryPolicy(base retry.Policy) retry.Policy {
+	policy := base
+	if so.retries != nil {
+		policy.Retries = *so.retries
+	}
+	if so.backoff != nil {
+		policy.Backoff = *so.backoff
+	}
+	return policy
+}
+
+// extractServiceOptions removes the router option keys from the URL query, returning the options they represent
+func extractServiceOptions(serviceURL *url.URL) (options serviceOptions, err error) {
+	query := serviceURL.Query()
+	found := false
+
+	for key, values := range query {
+		switch strings.ToLower(key) {
+		case RetriesKey:
+			retries, err := strconv.Atoi(values[0])
+			if err != nil || retries < 0 {
+				return options, fmt.Errorf("invalid value for %q: %q", RetriesKey, values[0])
+			}
+			options.retries = &retries
+		case BackoffKey:
+			backoff, err := time.ParseDuration(values[0])
+			if err != nil || backoff < 0 {
+				return options, fmt.Errorf("invalid value for %q: %q", BackoffKey, values[0])
+			}
+			options.backoff = &backoff
+		case RateLimitKey:
+			limit, err := ratelimit.ParseLimit(values[0])
+			if err != nil {
+				return options, fmt.Errorf("invalid value for %q: %w", RateLimitKey, err)
+			}
+			options.rateLimit = &limit
+		case FormatKey:
+			if options.format, err = parseFormat(values[0]); err != nil {
+				return options, err
+			}
+		default:
+			continue
+		}
+		query.Del(key)
+		found = true
+	}
+
+	// Only re-encode the query if it was changed, to preserve the original formatting
+	if found {
+		serviceURL.RawQuery = query.Encode()
+	}
+
+	return options, nil
+}
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StrategyMode selects how a message is delivered using the routers services
+type StrategyMode int
+
+const (
+	// BroadcastMode sends the message using all services at once
+	BroadcastMode StrategyMode = iota
+	// FailoverMode sends the message using one service at a time, in the order they were added, until one succeeds
+	FailoverMode
+	// QuorumMode sends the message using the first Quorum services at once, using the next service in order for each
+	// one that fails, until Quorum services have succeeded
+	QuorumMode
+)
+
+// Strategy determines which of the routers services are used for delivering a message, and when the delivery is
+// considered successful
+type Strategy struct {
+	Mode StrategyMode
+	// Quorum is the number of services that need to succeed when using QuorumMode. Values below 1 are treated as 1.
+	Quorum int
+}
+
+var (
+	// Broadcast delivers the message using all services, and requires all of them to succeed. It is the default.
+	Broadcast = Strategy{Mode: BroadcastMode}
+	// Failover delivers the message using the first service that succeeds
+	Failover = Strategy{Mode: FailoverMode}
+)
+
+// Quorum returns a strategy that delivers the message using the first n services that succeed
+func Quorum(n int) Strategy {
+	return Strategy{Mode: QuorumMode, Quorum: n}
+}
+
+// ParseStrategy parses a strategy in the format returned by Strategy.String, that is "broadcast", "failover" or
+// "quorum:N". A plain "quorum" is the same as "quorum:1".
+func ParseStrategy(s string) (Strategy, error) {
+	name, count, hasCount := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+	switch {
+	case name == "broadcast" && !hasCount:
+		return Broadcast, nil
+	case name == "failover" && !hasCount:
+		return Failover, nil
+	case name == "quorum" && !hasCount:
+		return Quorum(1), nil
+	case name == "quorum":
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return Strategy{}, fmt.Errorf("invalid quorum %q, expected a positive number", count)
+		}
+		return Quorum(n), nil
+	}
+	return Strategy{}, fmt.Errorf("unknown strategy %q, expected broadcast, failover or quorum:N", s)
+}
+
+func (s Strategy) String() string {
+	switch s.Mode {
+	case BroadcastMode:
+		return "broadcast"
+	case FailoverMode:
+		return "failover"
+	case QuorumMode:
+		return fmt.Sprintf("quorum:%d", s.required(-1))
+	}
+	return fmt.Sprintf("Strategy(%d)", s.Mode)
+}
+
+// required returns the number of services that need to succeed for the delivery to be successful. It is also the
+// number of services that are used at the same time. If serviceCount is not negative, the result is capped to it.
+func (s Strategy) required(serviceCount int) int {
+	required := serviceCount
+	switch s.Mode {
+	case FailoverMode:
+		required = 1
+	case QuorumMode:
+		required = s.Quorum
+		if required < 1 {
+			required = 1
+		}
+	}
+
+	if serviceCount >= 0 && required > serviceCount {
+		return serviceCount
+	}
+	return required
+}
+
+// Delivered returns whether the errors returned by sending a message satisfies the routers strategy. When using the
+// broadcast strategy this is only the case if no errors occurred, while other strategies allows some services to fail.
+// When using routing rules, use DeliveredResults instead, since the errors do not tell which services were selected.
+func (router *ServiceRouter) Delivered(errs []error) bool {
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	strategy := Broadcast
+	if router != nil {
+		strategy = router.Strategy
+	}
+	return succeeded >= strategy.required(len(errs))
+}
+
+// DeliveredResults returns whether the results of sending a message satisfies the routers strategy, only considering
+// the services that were selected by the routing rules
+func (router *ServiceRouter) DeliveredResults(results []SendResult) bool {
+	var errs []error
+	for _, result := range results {
+		if !result.unrouted {
+			errs = append(errs, result.Err)
+		}
+	}
+	return router.Delivered(errs)
+}
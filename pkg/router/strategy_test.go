@@ -0,0 +1,184 @@
+package router
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// strategyTestService fails to send if the host of its URL is "fail", and records the hosts that were used
+type strategyTestService struct {
+	standard.Standard
+	host string
+}
+
+var (
+	strategyTestLock     sync.Mutex
+	strategyTestSends    []string
+	strategyTestMessages []string
+)
+
+func (service *strategyTestService) Initialize(serviceURL *url.URL, _ t.StdLogger) error {
+	service.host = serviceURL.Host
+	return nil
+}
+
+func (service *strategyTestService) Send(message string, _ *t.Params) error {
+	strategyTestLock.Lock()
+	strategyTestSends = append(strategyTestSends, service.host)
+	strategyTestMessages = append(strategyTestMessages, message)
+	strategyTestLock.Unlock()
+
+	if service.host == "fail" {
+		return errors.New("send failed")
+	}
+	return nil
+}
+
+func sentHosts() []string {
+	strategyTestLock.Lock()
+	defer strategyTestLock.Unlock()
+	return append([]string{}, strategyTestSends...)
+}
+
+func sentMessages() []string {
+	strategyTestLock.Lock()
+	defer strategyTestLock.Unlock()
+	return append([]string{}, strategyTestMessages...)
+}
+
+func resetSends() {
+	strategyTestLock.Lock()
+	strategyTestSends = nil
+	strategyTestMessages = nil
+	strategyTestLock.Unlock()
+}
+
+func newStrategyRouter(strategy Strategy, hosts ...string) *ServiceRouter {
+	router, err := New(nil)
+	Expect(err).NotTo(HaveOccurred())
+	router.Strategy = strategy
+	for _, host := range hosts {
+		Expect(router.AddService("strategy-test://" + host)).To(Succeed())
+	}
+	return router
+}
+
+var _ = Describe("the delivery strategies", func() {
+	BeforeEach(resetSends)
+
+	When("using the broadcast strategy", func() {
+		It("should send using all services", func() {
+			router := newStrategyRouter(Broadcast, "a", "b", "c")
+			errs := router.Send("message", nil)
+			Expect(errs).To(Equal([]error{nil, nil, nil}))
+			Expect(sentHosts()).To(ConsistOf("a", "b", "c"))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+
+		It("should not be delivered if any service fails", func() {
+			router := newStrategyRouter(Broadcast, "a", "fail")
+			errs := router.Send("message", nil)
+			Expect(errs[1]).To(HaveOccurred())
+			Expect(router.Delivered(errs)).To(BeFalse())
+		})
+	})
+
+	When("using the failover strategy", func() {
+		It("should only send using the first service if it succeeds", func() {
+			router := newStrategyRouter(Failover, "a", "b", "c")
+			errs := router.Send("message", nil)
+			Expect(errs).To(Equal([]error{nil, nil, nil}))
+			Expect(sentHosts()).To(Equal([]string{"a"}))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+
+		It("should fall back to the next services in order until one succeeds", func() {
+			router := newStrategyRouter(Failover, "fail", "fail", "b", "c")
+			errs := router.Send("message", nil)
+			Expect(errs[0]).To(HaveOccurred())
+			Expect(errs[1]).To(HaveOccurred())
+			Expect(errs[2:]).To(Equal([]error{nil, nil}))
+			Expect(sentHosts()).To(Equal([]string{"fail", "fail", "b"}))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+
+		It("should not be delivered if all services fail", func() {
+			router := newStrategyRouter(Failover, "fail", "fail")
+			errs := router.Send("message", nil)
+			Expect(errs).To(HaveEach(HaveOccurred()))
+			Expect(router.Delivered(errs)).To(BeFalse())
+		})
+
+		It("should report one result for each service when sending asynchronously", func() {
+			router := newStrategyRouter(Failover, "fail", "a", "b")
+			var errs []error
+			for err := range router.SendAsync("message", nil) {
+				errs = append(errs, err)
+			}
+			Expect(errs).To(HaveLen(3))
+		})
+	})
+
+	When("using the quorum strategy", func() {
+		It("should send using the first N services if they succeed", func() {
+			router := newStrategyRouter(Quorum(2), "a", "b", "c")
+			errs := router.Send("message", nil)
+			Expect(sentHosts()).To(ConsistOf("a", "b"))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+
+		It("should use the next service for each one that fails", func() {
+			router := newStrategyRouter(Quorum(2), "a", "fail", "b", "c")
+			errs := router.Send("message", nil)
+			Expect(errs[1]).To(HaveOccurred())
+			Expect(sentHosts()).To(ConsistOf("a", "fail", "b"))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+
+		It("should not be delivered if too few services succeed", func() {
+			router := newStrategyRouter(Quorum(2), "a", "fail", "fail")
+			errs := router.Send("message", nil)
+			Expect(sentHosts()).To(ConsistOf("a", "fail", "fail"))
+			Expect(router.Delivered(errs)).To(BeFalse())
+		})
+
+		It("should require all services if the quorum is larger than the number of services", func() {
+			router := newStrategyRouter(Quorum(5), "a", "b")
+			errs := router.Send("message", nil)
+			Expect(sentHosts()).To(ConsistOf("a", "b"))
+			Expect(router.Delivered(errs)).To(BeTrue())
+		})
+	})
+
+	When("parsing a strategy", func() {
+		It("should parse all the strategies", func() {
+			for _, strategy := range []Strategy{Broadcast, Failover, Quorum(3)} {
+				parsed, err := ParseStrategy(strategy.String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(strategy))
+			}
+		})
+
+		It("should treat a quorum without a count as 1", func() {
+			Expect(ParseStrategy("quorum")).To(Equal(Quorum(1)))
+		})
+
+		It("should return an error for invalid strategies", func() {
+			for _, s := range []string{"", "all", "quorum:0", "quorum:x", "failover:2"} {
+				_, err := ParseStrategy(s)
+				Expect(err).To(HaveOccurred(), s)
+			}
+		})
+	})
+})
+
+func init() {
+	MustRegister("strategy-test", func() t.Service { return &strategyTestService{} })
+}
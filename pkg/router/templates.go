@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// TemplateData is the data that message templates are executed with
+type TemplateData struct {
+	// Message is the plain message, or the text of the message item being rendered
+	Message string
+	// Title is the title param of the message, if any
+	Title string
+	// Level is the level of the message item, or the level param of plain messages
+	Level t.MessageLevel
+	// Timestamp is the timestamp of the message item, or the time of sending if it has none
+	Timestamp time.Time
+	// Params are all the params of the message
+	Params t.Params
+}
+
+type templateKey struct{}
+
+// WithTemplate returns a context that makes the router render messages sent using it with the template, instead of
+// the template set using SetTemplate
+func WithTemplate(ctx context.Context, tpl *template.Template) context.Context {
+	return context.WithValue(ctx, templateKey{}, tpl)
+}
+
+// SetTemplate sets the template that all messages are rendered with before they are sent, which is executed with
+// TemplateData. A nil template sends the messages as is. Use templates.Parse for creating templates with the
+// helper functions available.
+func (router *ServiceRouter) SetTemplate(tpl *template.Template) {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.template = tpl
+}
+
+// messageTemplate returns the template to render the messages sent using ctx with, if any
+func (router *ServiceRouter) messageTemplate(ctx context.Context) *template.Template {
+	if tpl, found := ctx.Value(templateKey{}).(*template.Template); found {
+		return tpl
+	}
+
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	return router.template
+}
+
+// render replaces the plain text, or the text of every message item, with the output of the template
+func (m *Message) render(tpl *template.Template) error {
+	now := time.Now()
+	data := TemplateData{
+		Title:     m.Params[t.TitleKey],
+		Timestamp: now,
+		Params:    m.Params,
+	}
+
+	if !m.rich {
+		data.Message = m.Text
+		data.Level = m.notification().level(m.Params)
+		rendered, err := executeTemplate(tpl, data)
+		if err != nil {
+			return err
+		}
+		m.Text = rendered
+	}
+
+	for i, item := range m.Items {
+		data.Message = item.Text
+		data.Level = item.Level
+		data.Timestamp = item.Timestamp
+		if data.Timestamp.IsZero() {
+			data.Timestamp = now
+		}
+		rendered, err := executeTemplate(tpl, data)
+		if err != nil {
+			return err
+		}
+		m.Items[i].Text = rendered
+	}
+
+	m.templated = true
+	return nil
+}
+
+func executeTemplate(tpl *template.Template, data TemplateData) (string, error) {
+	sb := strings.Builder{}
+	if err := tpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,69 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/templates"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("the message templates", func() {
+	BeforeEach(resetSends)
+
+	It("should render plain messages with the title, level and params", func() {
+		router := newRulesRouter(nil, "a")
+		tpl, err := templates.Parse("message", `[{{ .Level }}] {{ .Title | upper }}: {{ .Message }} ({{ .Params.env }})`)
+		Expect(err).NotTo(HaveOccurred())
+		router.SetTemplate(tpl)
+
+		router.Send("disk full", &t.Params{"title": "web01", LevelKey: "warning", "env": "prod"})
+		Expect(sentMessages()).To(Equal([]string{"[Warning] WEB01: disk full (prod)"}))
+	})
+
+	It("should render every message item using its level and timestamp", func() {
+		router := newRulesRouter(nil, "a")
+		tpl, err := templates.Parse("message", `{{ .Timestamp | date "15:04" }} {{ .Level }}: {{ .Message }}`)
+		Expect(err).NotTo(HaveOccurred())
+		router.SetTemplate(tpl)
+
+		timestamp := time.Date(2023, 1, 2, 13, 37, 0, 0, time.UTC)
+		router.SendItems([]t.MessageItem{
+			{Text: "first", Level: t.Error, Timestamp: timestamp},
+			{Text: "second", Level: t.Info, Timestamp: timestamp},
+		}, nil)
+		Expect(sentMessages()).To(Equal([]string{"13:37 Error: first\n13:37 Info: second"}))
+	})
+
+	It("should use the template of the context for the send", func() {
+		router := newRulesRouter(nil, "a")
+		routerTpl, _ := templates.Parse("router", `router: {{ .Message }}`)
+		sendTpl, _ := templates.Parse("send", `send: {{ .Message }}`)
+		router.SetTemplate(routerTpl)
+
+		router.SendContext(WithTemplate(context.Background(), sendTpl), "message", nil)
+		router.Send("message", nil)
+		Expect(sentMessages()).To(Equal([]string{"send: message", "router: message"}))
+	})
+
+	It("should report template errors for all services", func() {
+		router := newRulesRouter(nil, "a", "b")
+		tpl, _ := templates.Parse("message", `{{ .Message | trunc "x" }}`)
+		router.SetTemplate(tpl)
+
+		Expect(router.Send("message", nil)).To(HaveEach(MatchError(ContainSubstring("failed to render message template"))))
+		Expect(sentHosts()).To(BeEmpty())
+	})
+
+	It("should only pass the params that a service supports when a template is used", func() {
+		router, err := New(nil, "ntfy://ntfy.sh/topic", "logger://")
+		Expect(err).NotTo(HaveOccurred())
+
+		params := t.Params{"title": "title", "env": "prod"}
+		Expect(router.services[0].sendParams(params, true)).To(Equal(t.Params{"title": "title"}))
+		Expect(router.services[0].sendParams(params, false)).To(Equal(params))
+	})
+})
@@ -0,0 +1,239 @@
+// Package server implements a HTTP API for sending notifications using a set of named targets
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// DefaultTarget is the name of the target that is used when a send request does not specify any targets
+const DefaultTarget = "default"
+
+// maxRequestSize is the maximum size of a send request body
+const maxRequestSize = 1 << 20
+
+// Server is a http.Handler that sends notifications using long-lived routers, one for each named target
+type Server struct {
+	logger  t.StdLogger
+	token   string
+	targets map[string]*router.ServiceRouter
+	mux     *http.ServeMux
+}
+
+// SendRequest is the payload of a POST /send request
+type SendRequest struct {
+	Message string   `json:"message"`
+	Title   string   `json:"title,omitempty"`
+	Params  t.Params `json:"params,omitempty"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+// SendResponse is the payload returned for a POST /send request
+type SendResponse struct {
+	Results []ServiceResult `json:"results"`
+}
+
+// ServiceResult is the outcome of sending a notification using a single service of a target
+type ServiceResult struct {
+	Target  string `json:"target"`
+	Service string `json:"service"`
+	Success bool   `json:"success"`
+	// Skipped is set if the service was not used for sending, such as when the strategy of the target did not need it
+	Skipped bool   `json:"skipped"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TargetInfo describes a configured target, as returned for a GET /services request
+type TargetInfo struct {
+	Name     string   `json:"name"`
+	Services []string `json:"services"`
+}
+
+// ErrorResponse is the payload returned when a request could not be handled
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// New creates a server with a router for each of the named targets, initialized using its service URLs.
+// If token is not empty, all requests except health checks must include it as a bearer token.
+func New(logger t.StdLogger, token string, targets map[string][]string) (*Server, error) {
+	if logger == nil {
+		logger = util.DiscardLogger
+	}
+
+	server := &Server{
+		logger:  logger,
+		token:   token,
+		targets: make(map[string]*router.ServiceRouter, len(targets)),
+		mux:     http.NewServeMux(),
+	}
+
+	for name, urls := range targets {
+		sr, err := router.New(logger, urls...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize target %q: %w", name, err)
+		}
+		server.targets[name] = sr
+	}
+
+	server.mux.HandleFunc("/health", server.handleHealth)
+	server.mux.Handle("/services", server.authorized(http.HandlerFunc(server.handleServices)))
+	server.mux.Handle("/send", server.authorized(http.HandlerFunc(server.handleSend)))
+
+	return server, nil
+}
+
+// Handle registers an additional handler for the given pattern, requiring the same authorization as the API
+func (server *Server) Handle(pattern string, handler http.Handler) {
+	server.mux.Handle(pattern, server.authorized(handler))
+}
+
+// Target returns the router used for the named target
+func (server *Server) Target(name string) (*router.ServiceRouter, bool) {
+	sr, found := server.targets[name]
+	return sr, found
+}
+
+// ServeHTTP dispatches the request to the handler of the matching API endpoint
+func (server *Server) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	server.mux.ServeHTTP(res, req)
+}
+
+func (server *Server) authorized(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if server.token != "" {
+			auth := req.Header.Get("Authorization")
+			token := strings.TrimPrefix(auth, "Bearer ")
+			if token == auth || subtle.ConstantTimeCompare([]byte(token), []byte(server.token)) != 1 {
+				res.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(res, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next.ServeHTTP(res, req)
+	})
+}
+
+func (server *Server) handleHealth(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		writeMethodNotAllowed(res, http.MethodGet, http.MethodHead)
+		return
+	}
+	writeJSON(res, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (server *Server) handleServices(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeMethodNotAllowed(res, http.MethodGet)
+		return
+	}
+
+	targets := make([]TargetInfo, 0, len(server.targets))
+	for name, sr := range server.targets {
+		targets = append(targets, TargetInfo{Name: name, Services: serviceNames(sr)})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	writeJSON(res, http.StatusOK, targets)
+}
+
+func (server *Server) handleSend(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeMethodNotAllowed(res, http.MethodPost)
+		return
+	}
+
+	request := SendRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(res, req.Body, maxRequestSize)).Decode(&request); err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if request.Message == "" {
+		writeError(res, http.StatusBadRequest, errors.New("message is required"))
+		return
+	}
+
+	names := request.Targets
+	if len(names) < 1 {
+		names = []string{DefaultTarget}
+	}
+
+	routers := make([]*router.ServiceRouter, len(names))
+	for i, name := range names {
+		sr, found := server.targets[name]
+		if !found {
+			writeError(res, http.StatusNotFound, fmt.Errorf("unknown target %q", name))
+			return
+		}
+		routers[i] = sr
+	}
+
+	params := t.Params{}
+	for key, value := range request.Params {
+		params[key] = value
+	}
+	if request.Title != "" {
+		params.SetTitle(request.Title)
+	}
+
+	response := SendResponse{Results: []ServiceResult{}}
+	status := http.StatusOK
+	for i, sr := range routers {
+		results := sr.SendContextWithResults(req.Context(), request.Message, &params)
+		for _, sent := range results {
+			result := ServiceResult{
+				Target:  names[i],
+				Service: sent.Scheme,
+				Success: sent.Err == nil && !sent.Skipped(),
+				Skipped: sent.Skipped(),
+			}
+			if sent.Err != nil {
+				result.Error = sent.Err.Error()
+				server.logger.Printf("Failed to send to target %q using %v: %v", names[i], sent.Scheme, sent.Err)
+			}
+			response.Results = append(response.Results, result)
+		}
+		if !sr.DeliveredResults(results) {
+			status = http.StatusBadGateway
+		}
+	}
+
+	writeJSON(res, status, response)
+}
+
+// serviceNames returns the service scheme of each of the routers services
+func serviceNames(sr *router.ServiceRouter) []string {
+	urls := sr.ServiceURLs()
+	names := make([]string, len(urls))
+	for i, serviceURL := range urls {
+		names[i], _, _ = sr.ExtractServiceName(serviceURL)
+	}
+	return names
+}
+
+func writeMethodNotAllowed(res http.ResponseWriter, allowed ...string) {
+	res.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(res, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
+
+func writeError(res http.ResponseWriter, status int, err error) {
+	writeJSON(res, status, ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(res http.ResponseWriter, status int, payload interface{}) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	_ = json.NewEncoder(res).Encode(payload)
+}
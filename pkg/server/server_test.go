@@ -0,0 +1,152 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containrrr/shoutrrr/internal/testutils"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/server"
+)
+
+var _ = Describe("the API server", func() {
+	var srv *server.Server
+
+	BeforeEach(func() {
+		httpmock.Activate()
+		httpmock.RegisterResponder("POST", "https://failing.example/hook", httpmock.NewStringResponder(500, ""))
+
+		var err error
+		srv, err = server.New(testutils.TestLogger(), "", map[string][]string{
+			server.DefaultTarget: {"logger://"},
+			"ops":                {"logger://", "generic://failing.example/hook"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	When("sending a message", func() {
+		It("should use the default target if none are specified", func() {
+			res := serve("POST", "/send", `{"message": "hello", "title": "greeting"}`)
+			Expect(res.Code).To(Equal(http.StatusOK))
+
+			response := server.SendResponse{}
+			Expect(json.Unmarshal(res.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Results).To(Equal([]server.ServiceResult{
+				{Target: server.DefaultTarget, Service: "logger", Success: true},
+			}))
+		})
+
+		It("should return the result of each service", func() {
+			res := serve("POST", "/send", `{"message": "hello", "targets": ["ops"]}`)
+			Expect(res.Code).To(Equal(http.StatusBadGateway))
+
+			response := server.SendResponse{}
+			Expect(json.Unmarshal(res.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Results).To(HaveLen(2))
+			Expect(response.Results[0]).To(Equal(server.ServiceResult{Target: "ops", Service: "logger", Success: true}))
+			Expect(response.Results[1].Service).To(Equal("generic"))
+			Expect(response.Results[1].Success).To(BeFalse())
+			Expect(response.Results[1].Error).NotTo(BeEmpty())
+		})
+
+		It("should report the services that were not used as skipped", func() {
+			sr, _ := srv.Target("ops")
+			sr.Strategy = router.Failover
+
+			res := serve("POST", "/send", `{"message": "hello", "targets": ["ops"]}`)
+			Expect(res.Code).To(Equal(http.StatusOK))
+
+			response := server.SendResponse{}
+			Expect(json.Unmarshal(res.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Results).To(Equal([]server.ServiceResult{
+				{Target: "ops", Service: "logger", Success: true},
+				{Target: "ops", Service: "generic", Skipped: true},
+			}))
+			Expect(httpmock.GetTotalCallCount()).To(BeZero())
+		})
+
+		It("should reject unknown targets", func() {
+			res := serve("POST", "/send", `{"message": "hello", "targets": ["dev"]}`)
+			Expect(res.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should reject requests without a message", func() {
+			Expect(serve("POST", "/send", `{"title": "hello"}`).Code).To(Equal(http.StatusBadRequest))
+			Expect(serve("POST", "/send", `not json`).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should only allow POST requests", func() {
+			res := serve("GET", "/send", "")
+			Expect(res.Code).To(Equal(http.StatusMethodNotAllowed))
+			Expect(res.Header().Get("Allow")).To(Equal("POST"))
+		})
+	})
+
+	When("listing the services", func() {
+		It("should return the services of each target", func() {
+			res := serve("GET", "/services", "")
+			Expect(res.Code).To(Equal(http.StatusOK))
+
+			targets := []server.TargetInfo{}
+			Expect(json.Unmarshal(res.Body.Bytes(), &targets)).To(Succeed())
+			Expect(targets).To(Equal([]server.TargetInfo{
+				{Name: server.DefaultTarget, Services: []string{"logger"}},
+				{Name: "ops", Services: []string{"logger", "generic"}},
+			}))
+		})
+	})
+
+	When("a token has been configured", func() {
+		BeforeEach(func() {
+			var err error
+			srv, err = server.New(nil, "secret", map[string][]string{server.DefaultTarget: {"logger://"}})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject requests without the bearer token", func() {
+			res := serve("POST", "/send", `{"message": "hello"}`)
+			Expect(res.Code).To(Equal(http.StatusUnauthorized))
+			Expect(res.Header().Get("WWW-Authenticate")).To(Equal("Bearer"))
+
+			req := httptest.NewRequest("GET", "/services", nil)
+			req.Header.Set("Authorization", "Bearer wrong")
+			recorder := httptest.NewRecorder()
+			srv.ServeHTTP(recorder, req)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should accept requests with the bearer token", func() {
+			req := httptest.NewRequest("POST", "/send", strings.NewReader(`{"message": "hello"}`))
+			req.Header.Set("Authorization", "Bearer secret")
+			recorder := httptest.NewRecorder()
+			srv.ServeHTTP(recorder, req)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should not require the token for health checks", func() {
+			Expect(serve("GET", "/health", "").Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	When("a target can not be initialized", func() {
+		It("should return an error", func() {
+			_, err := server.New(nil, "", map[string][]string{"broken": {"nope://"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
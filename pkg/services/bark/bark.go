@@ -1,12 +1,14 @@
 package bark
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -21,19 +23,39 @@ type Service struct {
 
 // Send a notification message to Bark
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Bark, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	if err := service.sendAPI(config, message); err != nil {
+	if err := service.sendAPI(ctx, &config, message); err != nil {
 		return fmt.Errorf("failed to send bark notification: %w", err)
 	}
 
 	return nil
 }
 
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	req, err := service.buildRequest(ctx, &config, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bark request: %w", err)
+	}
+	return []*http.Request{req}, nil
+}
+
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
@@ -46,8 +68,32 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 }
 
-func (service *Service) sendAPI(config *Config, message string) error {
+func (service *Service) sendAPI(ctx context.Context, config *Config, message string) error {
+	req, err := service.buildRequest(ctx, config, message)
+	if err != nil {
+		return err
+	}
+
 	response := apiResponse{}
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
+
+	if err := jsonClient.Do(req, &response); err != nil {
+		if jsonClient.ErrorResponse(err, &response) {
+			// apiResponse implements Error
+			return retry.WithStatusOf(&response, err)
+		}
+		return err
+	}
+
+	if response.Code != http.StatusOK {
+		return fmt.Errorf("unknown error")
+	}
+
+	return nil
+}
+
+// buildRequest returns the request for pushing the message
+func (service *Service) buildRequest(ctx context.Context, config *Config, message string) (*http.Request, error) {
 	request := PushPayload{
 		Body:      message,
 		DeviceKey: config.DeviceKey,
@@ -60,19 +106,7 @@ func (service *Service) sendAPI(config *Config, message string) error {
 		Icon:      config.Icon,
 		URL:       config.URL,
 	}
-	jsonClient := jsonclient.NewClient()
-
-	if err := jsonClient.Post(config.GetAPIURL("push"), &request, &response); err != nil {
-		if jsonClient.ErrorResponse(err, &response) {
-			// apiResponse implements Error
-			return &response
-		}
-		return err
-	}
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
 
-	if response.Code != http.StatusOK {
-		return fmt.Errorf("unknown error")
-	}
-
-	return nil
+	return jsonClient.NewRequest(ctx, http.MethodPost, config.GetAPIURL("push"), &request)
 }
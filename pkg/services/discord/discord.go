@@ -2,15 +2,19 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service providing Discord as a notification service
@@ -27,53 +31,150 @@ var limits = types.MessageLimit{
 }
 
 const (
-	hookURL = "https://discord.com/api/webhooks"
+	hookURL         = "https://discord.com/api/webhooks"
+	contentTypeJSON = "application/json"
 	// Only search this many runes for a good split position
 	maxSearchRunes = 100
 )
 
 // Send a notification message to discord
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to discord, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	var firstErr error
 
 	if service.config.JSON {
 		postURL := CreateAPIURLFromConfig(service.config)
-		firstErr = doSend([]byte(message), postURL)
-	} else {
-		batches := CreateItemsFromPlain(message, service.config.SplitLines)
-		for _, items := range batches {
-			if err := service.sendItems(items, params); err != nil {
-				service.Log(err)
-				if firstErr == nil {
-					firstErr = err
-				}
-			}
+		var req *http.Request
+		if req, firstErr = buildRequest(ctx, []byte(message), contentTypeJSON, postURL); firstErr == nil {
+			firstErr = service.doSend(req)
 		}
+	} else {
+		firstErr = service.sendBatches(ctx, CreateItemsFromPlain(message, service.config.SplitLines), params)
 	}
 
 	if firstErr != nil {
-		return fmt.Errorf("failed to send discord notification: %v", firstErr)
+		return fmt.Errorf("failed to send discord notification: %w", firstErr)
+	}
+	return nil
+}
+
+// SendMarkdownContext sends the Markdown message to discord, which renders it natively, aborting the request if ctx is
+// done. The message is never split by line, since that would break up formatted blocks.
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	if err := service.sendBatches(ctx, CreateItemsFromPlain(message, false), params); err != nil {
+		return fmt.Errorf("failed to send discord notification: %w", err)
 	}
 	return nil
 }
 
+// sendBatches sends each batch of items as a separate message, returning the first error. If any of the batches were
+// sent, the error is wrapped in a retry.PartialError, since retrying would send those batches again.
+func (service *Service) sendBatches(ctx context.Context, batches [][]types.MessageItem, params *types.Params) error {
+	var firstErr error
+	sent := 0
+	for _, items := range batches {
+		if err := service.sendItems(ctx, items, params); err != nil {
+			service.Log(err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		return retry.Partial(firstErr)
+	}
+	return firstErr
+}
+
+// BuildRequests returns the requests that SendContext would make for the message, without sending them
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	var requests []*http.Request
+	var err error
+
+	if service.config.JSON {
+		postURL := CreateAPIURLFromConfig(service.config)
+		var req *http.Request
+		if req, err = buildRequest(ctx, []byte(message), contentTypeJSON, postURL); err == nil {
+			requests = []*http.Request{req}
+		}
+	} else {
+		requests, err = service.buildBatchRequests(ctx, CreateItemsFromPlain(message, service.config.SplitLines), params)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discord requests: %w", err)
+	}
+	return requests, nil
+}
+
+// BuildMarkdownRequests returns the requests that SendMarkdownContext would make for the message, without sending them
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	requests, err := service.buildBatchRequests(ctx, CreateItemsFromPlain(message, false), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discord requests: %w", err)
+	}
+	return requests, nil
+}
+
+// buildBatchRequests returns a request for each batch of items
+func (service *Service) buildBatchRequests(ctx context.Context, batches [][]types.MessageItem, params *types.Params) ([]*http.Request, error) {
+	requests := make([]*http.Request, 0, len(batches))
+	for _, items := range batches {
+		req, err := service.buildItemsRequest(ctx, items, params)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// MessageLimit returns the limits of the messages sent to Discord, where plain messages are split into chunks
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
+}
+
 // SendItems sends items with additional meta data and richer appearance
-func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
-	return service.sendItems(items, params)
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends items as embeds, with their level, timestamp and fields, aborting the request if ctx is done.
+// Any attachments of the items are uploaded as files of the message.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	if err := service.sendItems(ctx, items, &params); err != nil {
+		return fmt.Errorf("failed to send discord notification: %w", err)
+	}
+	return nil
+}
+
+func (service *Service) sendItems(ctx context.Context, items []types.MessageItem, params *types.Params) error {
+	req, err := service.buildItemsRequest(ctx, items, params)
+	if err != nil {
+		return err
+	}
+	return service.doSend(req)
 }
 
-func (service *Service) sendItems(items []types.MessageItem, params *types.Params) error {
+// buildItemsRequest returns the request for posting the items as a message, with any attachments as files
+func (service *Service) buildItemsRequest(ctx context.Context, items []types.MessageItem, params *types.Params) (*http.Request, error) {
 	var err error
 
 	config := *service.config
 	if err = service.pkr.UpdateConfigFromParams(&config, params); err != nil {
-		return err
+		return nil, err
 	}
 
 	var payload WebhookPayload
 	payload, err = CreatePayloadFromItems(items, config.Title, config.LevelColors())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	payload.Username = config.Username
@@ -82,11 +183,20 @@ func (service *Service) sendItems(items []types.MessageItem, params *types.Param
 	var payloadBytes []byte
 	payloadBytes, err = json.Marshal(payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	postURL := CreateAPIURLFromConfig(&config)
-	return doSend(payloadBytes, postURL)
+
+	if attachments := types.ItemsAttachments(items); len(attachments) > 0 {
+		body, contentType, err := createMultipartBody(payloadBytes, attachments)
+		if err != nil {
+			return nil, err
+		}
+		return buildRequest(ctx, body, contentType, postURL)
+	}
+
+	return buildRequest(ctx, payloadBytes, contentTypeJSON, postURL)
 }
 
 // CreateItemsFromPlain creates a set of MessageItems that is compatible with Discords webhook payload
@@ -143,16 +253,41 @@ func CreateAPIURLFromConfig(config *Config) string {
 	}
 }
 
-func doSend(payload []byte, postURL string) error {
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
+func buildRequest(ctx context.Context, payload []byte, contentType string, postURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
+
+func (service *Service) doSend(req *http.Request) error {
+	res, err := service.GetHTTPClient().Do(req)
 
 	if res == nil && err == nil {
 		err = fmt.Errorf("unknown error")
 	}
 
-	if err == nil && res.StatusCode != http.StatusNoContent {
-		err = fmt.Errorf("response status code %s", res.Status)
+	if err == nil {
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusNoContent {
+			resErr := retry.NewResponseError(res, fmt.Errorf("response status code %s", res.Status))
+			if res.StatusCode == http.StatusTooManyRequests && resErr.Delay == 0 {
+				resErr.Delay = parseRateLimitDelay(res.Body)
+			}
+			err = resErr
+		}
 	}
 
 	return err
 }
+
+// parseRateLimitDelay returns the retry_after delay from a rate limited response body
+func parseRateLimitDelay(body io.Reader) time.Duration {
+	rateLimit := rateLimitResponse{}
+	if err := json.NewDecoder(body).Decode(&rateLimit); err != nil {
+		return 0
+	}
+	return time.Duration(rateLimit.RetryAfter * float64(time.Second))
+}
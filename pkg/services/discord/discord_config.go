@@ -12,7 +12,7 @@ import (
 type Config struct {
 	standard.EnumlessConfig
 	WebhookID  string `url:"host"`
-	Token      string `url:"user"`
+	Token      string `url:"user" secret:""`
 	Title      string `key:"title"      default:""`
 	Username   string `key:"username"   default:""         desc:"Override the webhook default username"`
 	Avatar     string `key:"avatar,avatarurl"     default:""         desc:"Override the webhook default avatar with specified URL"`
@@ -8,6 +8,13 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
+// Limits of the embed fields enforced by the discord API
+const (
+	maxEmbedFields      = 25
+	maxFieldNameLength  = 256
+	maxFieldValueLength = 1024
+)
+
 // WebhookPayload is the webhook endpoint payload
 type WebhookPayload struct {
 	Embeds    []embedItem `json:"embeds"`
@@ -22,9 +29,23 @@ type embedItem struct {
 	URL       string       `json:"url,omitempty"`
 	Timestamp string       `json:"timestamp,omitempty"`
 	Color     uint         `json:"color,omitempty"`
+	Fields    []embedField `json:"fields,omitempty"`
 	Footer    *embedFooter `json:"footer,omitempty"`
 }
 
+// rateLimitResponse is the body of a rate limited (HTTP 429) response
+type rateLimitResponse struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
 type embedFooter struct {
 	Text    string `json:"text"`
 	IconURL string `json:"icon_url,omitempty"`
@@ -63,6 +84,17 @@ func CreatePayloadFromItems(items []types.MessageItem, title string, colors [typ
 			ei.Timestamp = item.Timestamp.UTC().Format(time.RFC3339)
 		}
 
+		for i, field := range item.Fields {
+			if i >= maxEmbedFields {
+				break
+			}
+			ei.Fields = append(ei.Fields, embedField{
+				Name:   util.Ellipsis(field.Key, maxFieldNameLength),
+				Value:  util.Ellipsis(field.Value, maxFieldValueLength),
+				Inline: true,
+			})
+		}
+
 		embeds = append(embeds, ei)
 	}
 
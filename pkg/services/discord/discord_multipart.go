@@ -0,0 +1,49 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// maxAttachments is the number of files that the discord API allows in a single message
+const maxAttachments = 10
+
+// createMultipartBody creates a multipart/form-data request body with the JSON payload and the attachments as files,
+// returning the body and its content type
+func createMultipartBody(payload []byte, attachments []types.Attachment) ([]byte, string, error) {
+	if len(attachments) > maxAttachments {
+		return nil, "", fmt.Errorf("too many attachments, discord allows at most %d per message", maxAttachments)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("payload_json", string(payload)); err != nil {
+		return nil, "", err
+	}
+
+	for i, attachment := range attachments {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition",
+			fmt.Sprintf(`form-data; name="files[%d]"; filename=%q`, i, attachment.Name))
+		header.Set("Content-Type", attachment.MediaType())
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(attachment.Content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
@@ -1,6 +1,7 @@
 package discord_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -8,8 +9,10 @@ import (
 	"github.com/containrrr/shoutrrr/internal/testutils"
 	. "github.com/containrrr/shoutrrr/pkg/services/discord"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 	"github.com/jarcoal/httpmock"
 
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
@@ -203,6 +206,18 @@ var _ = Describe("the discord service", func() {
 				Expect(item.Title).To(Equal("Title"))
 				Expect(item.Color).To(Equal(dummyColors[types.Warning]))
 			})
+
+			It("should add the item fields to the embed", func() {
+				item := types.MessageItem{Text: "Message"}
+				item.WithField("Host", "db-1").WithField("Disk", "95%")
+				payload, err := CreatePayloadFromItems([]types.MessageItem{item}, "", dummyColors)
+				Expect(err).ToNot(HaveOccurred())
+
+				payloadBytes, err := json.Marshal(payload)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(payloadBytes)).To(ContainSubstring(
+					`"fields":[{"name":"Host","value":"db-1","inline":true},{"name":"Disk","value":"95%","inline":true}]`))
+			})
 		})
 	})
 
@@ -227,11 +242,44 @@ var _ = Describe("the discord service", func() {
 
 			Expect(service.Send("Message", nil)).To(Succeed())
 		})
+		It("should send message items as embeds", func() {
+			setupResponder(&dummyConfig, 204, "")
+
+			items := []types.MessageItem{{Text: "Message", Level: types.Error}}
+			Expect(service.SendItems(items, types.Params{"title": "Title"})).To(Succeed())
+		})
+		It("should upload the attachments as files of the message", func() {
+			var payloadJSON, filename string
+			httpmock.RegisterResponder("POST", CreateAPIURLFromConfig(&dummyConfig), func(req *http.Request) (*http.Response, error) {
+				payloadJSON = req.FormValue("payload_json")
+				if _, header, err := req.FormFile("files[0]"); err == nil {
+					filename = header.Filename
+				}
+				return httpmock.NewStringResponse(204, ""), nil
+			})
+
+			items := []types.MessageItem{{
+				Text:        "Message",
+				Attachments: []types.Attachment{types.NewAttachment("server.log", []byte("log"))},
+			}}
+			Expect(service.SendItems(items, nil)).To(Succeed())
+			Expect(payloadJSON).To(ContainSubstring(`"description":"Message"`))
+			Expect(filename).To(Equal("server.log"))
+		})
 		It("should report an error if the server response is not OK", func() {
 			setupResponder(&dummyConfig, 400, "")
 			Expect(service.Initialize(dummyConfig.GetURL(), logger)).To(Succeed())
 			Expect(service.Send("Message", nil)).NotTo(Succeed())
 		})
+		It("should report the requested delay if the request was rate limited", func() {
+			setupResponder(&dummyConfig, 429, `{"message": "You are being rate limited.", "retry_after": 1.5, "global": false}`)
+			err := service.Send("Message", nil)
+			Expect(err).To(HaveOccurred())
+
+			transient, delay := retry.IsTransient(err)
+			Expect(transient).To(BeTrue())
+			Expect(delay).To(Equal(1500 * time.Millisecond))
+		})
 		It("should report an error if the message is empty", func() {
 			setupResponder(&dummyConfig, 204, "")
 			Expect(service.Initialize(dummyConfig.GetURL(), logger)).To(Succeed())
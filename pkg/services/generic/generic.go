@@ -1,10 +1,12 @@
 package generic
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 
 	"bytes"
 	"fmt"
@@ -23,6 +25,34 @@ type Service struct {
 
 // Send a notification message to a generic webhook endpoint
 func (service *Service) Send(message string, paramsPtr *types.Params) error {
+	return service.SendContext(context.Background(), message, paramsPtr)
+}
+
+// SendContext sends a notification message to a generic webhook endpoint, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, paramsPtr *types.Params) error {
+	req, err := service.buildRequest(ctx, message, paramsPtr)
+	if err == nil {
+		err = service.doSend(req)
+	}
+
+	if err != nil {
+		return fmt.Errorf("an error occurred while sending notification to generic webhook: %w", err)
+	}
+
+	return nil
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, paramsPtr *types.Params) ([]*http.Request, error) {
+	req, err := service.buildRequest(ctx, message, paramsPtr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build generic webhook request: %w", err)
+	}
+	return []*http.Request{req}, nil
+}
+
+// buildRequest returns the webhook request for the message, with the params applied to a copy of the config
+func (service *Service) buildRequest(ctx context.Context, message string, paramsPtr *types.Params) (*http.Request, error) {
 	config := *service.config
 
 	var params types.Params
@@ -39,11 +69,22 @@ func (service *Service) Send(message string, paramsPtr *types.Params) error {
 	// Create a mutable copy of the passed params
 	sendParams := createSendParams(&config, params, message)
 
-	if err := service.doSend(&config, sendParams); err != nil {
-		return fmt.Errorf("an error occurred while sending notification to generic webhook: %s", err.Error())
+	postURL := config.WebhookURL().String()
+	payload, err := service.getPayload(&config, sendParams)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	req, err := http.NewRequestWithContext(ctx, config.RequestMethod, postURL, payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", config.ContentType)
+	req.Header.Set("Accept", config.ContentType)
+	for key, value := range config.headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -69,32 +110,17 @@ func (*Service) GetConfigURLFromCustom(customURL *url.URL) (serviceURL *url.URL,
 	return config.getURL(&pkr), nil
 }
 
-func (service *Service) doSend(config *Config, params types.Params) error {
-	postURL := config.WebhookURL().String()
-	payload, err := service.getPayload(config, params)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(config.RequestMethod, postURL, payload)
-	if err == nil {
-		req.Header.Set("Content-Type", config.ContentType)
-		req.Header.Set("Accept", config.ContentType)
-		for key, value := range config.headers {
-			req.Header.Set(key, value)
-		}
-		var res *http.Response
-		res, err = http.DefaultClient.Do(req)
-		if res != nil && res.Body != nil {
-			defer res.Body.Close()
-			if body, errRead := io.ReadAll(res.Body); errRead == nil {
-				service.Log("Server response: ", string(body))
-			}
-		}
-		if err == nil && res.StatusCode >= http.StatusMultipleChoices {
-			err = fmt.Errorf("server returned response status code %s", res.Status)
+func (service *Service) doSend(req *http.Request) error {
+	res, err := service.GetHTTPClient().Do(req)
+	if res != nil && res.Body != nil {
+		defer res.Body.Close()
+		if body, errRead := io.ReadAll(res.Body); errRead == nil {
+			service.Log("Server response: ", string(body))
 		}
 	}
+	if err == nil && res.StatusCode >= http.StatusMultipleChoices {
+		err = retry.NewResponseError(res, fmt.Errorf("server returned response status code %s", res.Status))
+	}
 
 	return err
 }
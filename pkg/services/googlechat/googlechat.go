@@ -2,6 +2,7 @@ package googlechat
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service providing Google Chat as a notification service.
@@ -28,33 +31,70 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 }
 
 // Send a notification message to Google Chat.
-func (service *Service) Send(message string, _ *types.Params) error {
-	config := service.config
+func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
 
-	jsonBody, err := json.Marshal(JSON{
-		Text: message,
-	})
+// SendContext sends a notification message to Google Chat, aborting the request if ctx is done.
+func (service *Service) SendContext(ctx context.Context, message string, _ *types.Params) error {
+	req, err := service.buildRequest(ctx, message)
 	if err != nil {
 		return err
 	}
 
-	postURL := getAPIURL(config)
-
-	jsonBuffer := bytes.NewBuffer(jsonBody)
-	resp, err := http.Post(postURL.String(), "application/json", jsonBuffer)
+	resp, err := service.GetHTTPClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send notification to Google Chat: %s", err)
+		return fmt.Errorf("failed to send notification to Google Chat: %w", err)
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Google Chat API notification returned %d HTTP status code", resp.StatusCode)
+		return retry.NewResponseError(resp, fmt.Errorf("Google Chat API notification returned %d HTTP status code", resp.StatusCode))
 	}
 
 	return nil
 }
 
+// SendMarkdownContext converts the Markdown message to the text formatting of Google Chat and sends it, aborting the
+// request if ctx is done.
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.SendContext(ctx, markdown.ToGoogleChat(message), params)
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it.
+func (service *Service) BuildRequests(ctx context.Context, message string, _ *types.Params) ([]*http.Request, error) {
+	req, err := service.buildRequest(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it.
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.BuildRequests(ctx, markdown.ToGoogleChat(message), params)
+}
+
+func (service *Service) buildRequest(ctx context.Context, message string) (*http.Request, error) {
+	jsonBody, err := json.Marshal(JSON{
+		Text: message,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	postURL := getAPIURL(service.config)
+
+	jsonBuffer := bytes.NewBuffer(jsonBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL.String(), jsonBuffer)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
 func getAPIURL(config *Config) *url.URL {
 	query := url.Values{}
 	query.Set("key", config.Key)
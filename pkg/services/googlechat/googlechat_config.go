@@ -14,8 +14,8 @@ type Config struct {
 	standard.EnumlessConfig
 	Host  string `default:"chat.googleapis.com"`
 	Path  string
-	Token string
-	Key   string
+	Token string `secret:""`
+	Key   string `secret:""`
 }
 
 // GetURL returns a URL representation of it's current field values
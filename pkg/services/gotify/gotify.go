@@ -1,10 +1,12 @@
 package gotify
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service providing Gotify as a notification service
@@ -32,21 +35,63 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	service.pkr = format.NewPropKeyResolver(service.config)
 	err := service.config.SetURL(configURL)
 
-	service.httpClient = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				// If DisableTLS is specified, we might still need to disable TLS verification
-				// since the default configuration of Gotify redirects HTTP to HTTPS
-				// Note that this cannot be overridden using params, only using the config URL
-				InsecureSkipVerify: service.config.DisableTLS,
+	service.initClient()
+
+	return err
+}
+
+// SetHTTPClient sets the client used for the requests to the Gotify server, replacing the default client. If DisableTLS
+// is set, a copy of the client that skips the TLS verification is used instead.
+func (service *Service) SetHTTPClient(client *http.Client) {
+	service.Standard.SetHTTPClient(client)
+	if service.config != nil {
+		service.initClient()
+	}
+}
+
+// initClient sets up the client used for requests, using the client set by SetHTTPClient if there is one
+func (service *Service) initClient() {
+	service.httpClient = service.Standard.GetHTTPClient()
+	if service.httpClient == http.DefaultClient {
+		service.httpClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					// If DisableTLS is specified, we might still need to disable TLS verification
+					// since the default configuration of Gotify redirects HTTP to HTTPS
+					// Note that this cannot be overridden using params, only using the config URL
+					InsecureSkipVerify: service.config.DisableTLS,
+				},
 			},
-		},
-		// Set a reasonable timeout to prevent one bad transfer from block all subsequent ones
-		Timeout: 10 * time.Second,
+			// Set a reasonable timeout to prevent one bad transfer from block all subsequent ones
+			Timeout: 10 * time.Second,
+		}
+	} else if service.config.DisableTLS {
+		service.httpClient = insecureClient(service.httpClient)
 	}
 	service.client = jsonclient.NewWithHTTPClient(service.httpClient)
+}
 
-	return err
+// insecureClient returns a copy of the client that skips the TLS verification, keeping the rest of its transport
+// settings, such as the proxy and client certificates. Clients with custom transports are returned as is.
+func insecureClient(client *http.Client) *http.Client {
+	roundTripper := client.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true
+
+	insecure := *client
+	insecure.Transport = transport
+	return &insecure
 }
 
 const tokenChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.-_"
@@ -82,35 +127,93 @@ func buildURL(config *Config) (string, error) {
 
 // Send a notification message to Gotify
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Gotify, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	return service.send(ctx, message, params, nil)
+}
+
+// SendMarkdownContext sends the Markdown message to Gotify, making the clients render it as Markdown, aborting the
+// request if ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.send(ctx, message, params, markdownExtras())
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	return service.buildRequests(ctx, &config, message, params, nil)
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	return service.buildRequests(ctx, &config, message, params, markdownExtras())
+}
+
+func (service *Service) buildRequests(ctx context.Context, config *Config, message string, params *types.Params, extras *messageExtras) ([]*http.Request, error) {
+	req, err := service.buildRequest(ctx, config, message, params, extras)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// markdownExtras returns the message extras that makes the clients render the message as Markdown
+func markdownExtras() *messageExtras {
+	return &messageExtras{
+		ClientDisplay: clientDisplay{ContentType: "text/markdown"},
+	}
+}
+
+func (service *Service) send(ctx context.Context, message string, params *types.Params, extras *messageExtras) error {
+	config := *service.config
+	req, err := service.buildRequest(ctx, &config, message, params, extras)
+	if err != nil {
+		return err
+	}
+
+	response := &messageResponse{}
+	err = service.client.Do(req, response)
+	if err != nil {
+		errorRes := &errorResponse{}
+		if service.client.ErrorResponse(err, errorRes) {
+			return retry.WithStatusOf(errorRes, err)
+		}
+		return fmt.Errorf("failed to send notification to Gotify: %w", err)
+	}
+
+	types.ReportMessageID(ctx, strconv.FormatUint(response.ID, 10))
+	return nil
+}
+
+// buildRequest returns the request for posting the message, updating config from the params
+func (service *Service) buildRequest(ctx context.Context, config *Config, message string, params *types.Params, extras *messageExtras) (*http.Request, error) {
 	if params == nil {
 		params = &types.Params{}
 	}
-	config := service.config
 	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
 		service.Logf("Failed to update params: %v", err)
 	}
 
 	postURL, err := buildURL(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	request := &messageRequest{
 		Message:  message,
 		Title:    config.Title,
 		Priority: config.Priority,
+		Extras:   extras,
 	}
-	response := &messageResponse{}
-	err = service.client.Post(postURL, request, response)
+	req, err := service.client.NewRequest(ctx, http.MethodPost, postURL, request)
 	if err != nil {
-		errorRes := &errorResponse{}
-		if service.client.ErrorResponse(err, errorRes) {
-			return errorRes
-		}
-		return fmt.Errorf("failed to send notification to Gotify: %s", err)
+		return nil, fmt.Errorf("failed to send notification to Gotify: %w", err)
 	}
-
-	return nil
+	return req, nil
 }
 
 // GetHTTPClient is only supposed to be used for mocking the httpclient when testing
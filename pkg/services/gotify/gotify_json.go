@@ -4,9 +4,21 @@ import "fmt"
 
 // messageRequest is the actual payload being sent to the Gotify API
 type messageRequest struct {
-	Message  string `json:"message"`
-	Title    string `json:"title"`
-	Priority int    `json:"priority"`
+	Message  string         `json:"message"`
+	Title    string         `json:"title"`
+	Priority int            `json:"priority"`
+	Extras   *messageExtras `json:"extras,omitempty"`
+}
+
+// messageExtras are the optional extra properties of a message, which are used by the Gotify clients
+type messageExtras struct {
+	ClientDisplay clientDisplay `json:"client::display"`
+}
+
+// clientDisplay sets how the clients should display the message, where ContentType is either text/plain or
+// text/markdown
+type clientDisplay struct {
+	ContentType string `json:"contentType"`
 }
 
 type messageResponse struct {
@@ -2,8 +2,10 @@ package gotify
 
 import (
 	"log"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/containrrr/shoutrrr/internal/testutils"
 
@@ -19,6 +21,36 @@ func TestGotify(t *testing.T) {
 
 var logger *log.Logger
 
+var _ = Describe("the Gotify HTTP client", func() {
+	It("should skip the TLS verification of the shared client if DisableTLS is set", func() {
+		proxy, _ := url.Parse("http://proxy.example:3128")
+		shared := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}, Timeout: time.Minute}
+
+		service := &Service{}
+		service.SetHTTPClient(shared)
+		serviceURL, _ := url.Parse("gotify://my.gotify.tld/Aaa.bbb.ccc.ddd?disabletls=yes")
+		Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+		transport := service.httpClient.Transport.(*http.Transport)
+		Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		Expect(transport.Proxy(&http.Request{URL: serviceURL})).To(Equal(proxy))
+		Expect(service.httpClient.Timeout).To(Equal(time.Minute))
+		if sharedTLS := shared.Transport.(*http.Transport).TLSClientConfig; sharedTLS != nil {
+			Expect(sharedTLS.InsecureSkipVerify).To(BeFalse())
+		}
+	})
+
+	It("should use the shared client as is if DisableTLS is not set", func() {
+		shared := &http.Client{Transport: &http.Transport{}}
+
+		service := &Service{}
+		service.SetHTTPClient(shared)
+		serviceURL, _ := url.Parse("gotify://my.gotify.tld/Aaa.bbb.ccc.ddd")
+		Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+		Expect(service.httpClient).To(BeIdenticalTo(shared))
+	})
+})
+
 var _ = Describe("the Gotify plugin URL building and token validation functions", func() {
 	It("should build a valid gotify URL", func() {
 		config := Config{
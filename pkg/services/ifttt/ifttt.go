@@ -2,6 +2,7 @@ package ifttt
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 const (
@@ -39,26 +41,56 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to a IFTTT webhook
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to a IFTTT webhook, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	payload, err := createJSONToSend(config, message, params)
+	payload, err := createJSONToSend(&config, message, params)
 	fmt.Printf("%+v", payload)
 	if err != nil {
 		return err
 	}
 	for _, event := range config.Events {
-		apiURL := service.createAPIURLForEvent(event)
-		err := doSend(payload, apiURL)
+		req, err := service.buildEventRequest(ctx, payload, event)
+		if err == nil {
+			err = service.doSend(req)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to send IFTTT event \"%s\": %s", event, err)
+			return fmt.Errorf("failed to send IFTTT event \"%s\": %w", event, err)
 		}
 	}
 	return nil
 }
 
+// BuildRequests returns the requests that SendContext would make for the message, one for each event, without sending
+// them
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	payload, err := createJSONToSend(&config, message, params)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]*http.Request, 0, len(config.Events))
+	for _, event := range config.Events {
+		req, err := service.buildEventRequest(ctx, payload, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request for IFTTT event \"%s\": %w", event, err)
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
 // CreateAPIURLForEvent creates a IFTTT webhook URL for the given event
 func (service *Service) createAPIURLForEvent(event string) string {
 	return fmt.Sprintf(
@@ -68,13 +100,23 @@ func (service *Service) createAPIURLForEvent(event string) string {
 	)
 }
 
-func doSend(payload []byte, postURL string) error {
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
+func (service *Service) buildEventRequest(ctx context.Context, payload []byte, event string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, service.createAPIURLForEvent(event), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (service *Service) doSend(req *http.Request) error {
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 	if res.StatusCode > 299 || res.StatusCode < 200 {
-		return fmt.Errorf("got response status code %s", res.Status)
+		return retry.NewResponseError(res, fmt.Errorf("got response status code %s", res.Status))
 	}
 	return nil
 }
@@ -16,7 +16,7 @@ const (
 // Config is the configuration needed to send IFTTT notifications
 type Config struct {
 	standard.EnumlessConfig
-	WebHookID         string   `url:"host" required:"true"`
+	WebHookID         string   `url:"host" required:"true" secret:""`
 	Events            []string `key:"events" required:"true"`
 	Value1            string   `key:"value1" optional:""`
 	Value2            string   `key:"value2" optional:""`
@@ -1,6 +1,7 @@
 package join
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 const (
@@ -25,7 +27,31 @@ type Service struct {
 
 // Send a notification message to Pushover
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Join, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	req, err := service.buildRequest(ctx, message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.sendRequest(req, strings.Join(service.config.Devices, ","))
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	req, err := service.buildRequest(ctx, message, params)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// buildRequest returns the request for sending the message to the devices, using the title and icon params if set
+func (service *Service) buildRequest(ctx context.Context, message string, params *types.Params) (*http.Request, error) {
+	config := *service.config
 	if params == nil {
 		params = &types.Params{}
 	}
@@ -42,15 +68,9 @@ func (service *Service) Send(message string, params *types.Params) error {
 
 	devices := strings.Join(config.Devices, ",")
 
-	return service.sendToDevices(devices, message, title, icon)
-}
-
-func (service *Service) sendToDevices(devices string, message string, title string, icon string) error {
-	config := service.config
-
 	apiURL, err := url.Parse(hookURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	data := url.Values{}
@@ -68,17 +88,24 @@ func (service *Service) sendToDevices(devices string, message string, title stri
 
 	apiURL.RawQuery = data.Encode()
 
-	res, err := http.Post(
-		apiURL.String(),
-		contentType,
-		nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return req, nil
+}
 
+func (service *Service) sendRequest(req *http.Request, devices string) error {
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to join devices %q, response status %q", devices, res.Status)
+		return retry.NewResponseError(res, fmt.Errorf("failed to send notification to join devices %q, response status %q", devices, res.Status))
 	}
 
 	return nil
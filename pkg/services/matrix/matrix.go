@@ -1,11 +1,16 @@
 package matrix
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+	"net/http"
 	"net/url"
+	"sync"
 )
 
 // Scheme is the identifying part of this service's configuration URL
@@ -17,9 +22,12 @@ type Service struct {
 	config *Config
 	client *client
 	pkr    format.PropKeyResolver
+	// loginLock prevents concurrent sends from logging in more than once
+	loginLock sync.Mutex
 }
 
-// Initialize loads ServiceConfig from configURL and sets logger for this Service
+// Initialize loads ServiceConfig from configURL and sets logger for this Service. If the config has a user, the service
+// logs in to the server when sending the first message, or when calling Verify, so that no requests are made before that.
 func (s *Service) Initialize(configURL *url.URL, logger t.StdLogger) error {
 	s.SetLogger(logger)
 	s.config = &Config{}
@@ -29,29 +37,134 @@ func (s *Service) Initialize(configURL *url.URL, logger t.StdLogger) error {
 		return err
 	}
 
-	s.client = newClient(s.config.Host, s.config.DisableTLS, logger)
-	if s.config.User != "" {
-		return s.client.login(s.config.User, s.config.Password)
+	s.client = newClient(s.GetHTTPClient(), s.config.Host, s.config.DisableTLS, logger)
+	if s.config.User == "" {
+		s.client.useToken(s.config.Password)
 	}
-
-	s.client.useToken(s.config.Password)
 	return nil
 }
 
+// login logs in to the server using the user and password of the config, unless it uses a token or has already logged in
+func (s *Service) login(ctx context.Context) error {
+	s.loginLock.Lock()
+	defer s.loginLock.Unlock()
+
+	if s.config.User == "" || s.client.accessToken != "" {
+		return nil
+	}
+	return s.client.login(ctx, s.config.User, s.config.Password)
+}
+
+// Verify logs in to the server using the user and password of the config, so that invalid credentials are reported
+// without sending a message. It does nothing for configs using a token, or if the service has already logged in.
+func (s *Service) Verify(ctx context.Context) error {
+	return s.login(ctx)
+}
+
+// SetHTTPClient sets the client used for the requests to the Matrix server
+func (s *Service) SetHTTPClient(client *http.Client) {
+	s.Standard.SetHTTPClient(client)
+	if s.client != nil {
+		s.client.httpClient = s.GetHTTPClient()
+	}
+}
+
 // Send notification
 func (s *Service) Send(message string, params *t.Params) error {
+	return s.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification, aborting any pending requests if ctx is done
+func (s *Service) SendContext(ctx context.Context, message string, params *t.Params) error {
 	config := *s.config
 	if err := s.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
+	if err := s.login(ctx); err != nil {
+		return err
+	}
+
+	return s.send(ctx, apiReqSend{MsgType: msgTypeText, Body: message}, config.Rooms)
+}
+
+// SendMarkdownContext sends the Markdown message with a HTML formatted body, and the message converted to plain text
+// as the body for clients that does not support formatting, aborting any pending requests if ctx is done
+func (s *Service) SendMarkdownContext(ctx context.Context, message string, params *t.Params) error {
+	config := *s.config
+	if err := s.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+	if err := s.login(ctx); err != nil {
+		return err
+	}
 
-	errors := s.client.sendMessage(message, s.config.Rooms)
+	return s.send(ctx, apiReqSend{
+		MsgType:       msgTypeText,
+		Body:          markdown.ToPlain(message),
+		Format:        formatHTML,
+		FormattedBody: markdown.ToHTML(message),
+	}, config.Rooms)
+}
+
+// SendItems sends the message items as a HTML formatted message
+func (s *Service) SendItems(items []t.MessageItem, params t.Params) error {
+	return s.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the message items as a HTML formatted message, with their level, timestamp and fields,
+// aborting any pending requests if ctx is done. Any attachments of the items are uploaded to the server and sent as
+// file or image messages after it.
+func (s *Service) SendItemsContext(ctx context.Context, items []t.MessageItem, params t.Params) error {
+	config := *s.config
+	if err := s.pkr.UpdateConfigFromParams(&config, &params); err != nil {
+		return err
+	}
+	if err := s.login(ctx); err != nil {
+		return err
+	}
+
+	var fileMessages []apiReqSend
+	for _, file := range t.ItemsAttachments(items) {
+		message, err := s.client.uploadMedia(ctx, file)
+		if err != nil {
+			return err
+		}
+		fileMessages = append(fileMessages, message)
+	}
+
+	var messages []apiReqSend
+	if message := createItemsMessage(items, config.Title); message.Body != "" || len(fileMessages) == 0 {
+		messages = append(messages, message)
+	}
+	messages = append(messages, fileMessages...)
+
+	for i, message := range messages {
+		if err := s.send(ctx, message, config.Rooms); err != nil {
+			if i > 0 {
+				// The previous messages have already been sent
+				return retry.Partial(err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send sends the message to the rooms. If it was sent to any of them, the error is wrapped in a retry.PartialError,
+// since retrying would send it to those rooms again.
+func (s *Service) send(ctx context.Context, message apiReqSend, rooms []string) error {
+	sent, errors := s.client.sendMessage(ctx, message, rooms)
 
 	if len(errors) > 0 {
 		for _, err := range errors {
 			s.Logf("error sending message: %w", err)
 		}
-		return fmt.Errorf("%v error(s) sending message, with initial error: %v", len(errors), errors[0])
+		err := fmt.Errorf("%v error(s) sending message, with initial error: %w", len(errors), errors[0])
+		if sent > 0 {
+			return retry.Partial(err)
+		}
+		return err
 	}
 
 	return nil
@@ -9,12 +9,16 @@ const (
 	apiRoomJoin    = "/_matrix/client/r0/join/%s"
 	apiSendMessage = "/_matrix/client/r0/rooms/%s/send/m.room.message"
 	apiJoinedRooms = "/_matrix/client/r0/joined_rooms"
+	apiUploadMedia = "/_matrix/media/r0/upload"
 
 	contentType = "application/json"
 
 	accessTokenKey = "access_token"
 
 	msgTypeText       messageType    = "m.text"
+	msgTypeFile       messageType    = "m.file"
+	msgTypeImage      messageType    = "m.image"
+	formatHTML                       = "org.matrix.custom.html"
 	flowLoginPassword flowType       = "m.login.password"
 	idTypeUser        identifierType = "m.id.user"
 )
@@ -38,8 +42,21 @@ type apiResLogin struct {
 }
 
 type apiReqSend struct {
-	MsgType messageType `json:"msgtype"`
-	Body    string      `json:"body"`
+	MsgType       messageType `json:"msgtype"`
+	Body          string      `json:"body"`
+	Format        string      `json:"format,omitempty"`
+	FormattedBody string      `json:"formatted_body,omitempty"`
+	URL           string      `json:"url,omitempty"`
+	Info          *mediaInfo  `json:"info,omitempty"`
+}
+
+type mediaInfo struct {
+	MimeType string `json:"mimetype"`
+	Size     int    `json:"size"`
+}
+
+type apiResUpload struct {
+	ContentURI string `json:"content_uri"`
 }
 
 type apiResRoom struct {
@@ -2,6 +2,7 @@ package matrix
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -11,17 +12,20 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 type client struct {
 	apiURL      url.URL
 	accessToken string
 	logger      types.StdLogger
+	httpClient  *http.Client
 }
 
-func newClient(host string, disableTLS bool, logger types.StdLogger) (c *client) {
+func newClient(httpClient *http.Client, host string, disableTLS bool, logger types.StdLogger) (c *client) {
 	c = &client{
-		logger: logger,
+		logger:     logger,
+		httpClient: httpClient,
 		apiURL: url.URL{
 			Host:   host,
 			Scheme: "https",
@@ -46,12 +50,12 @@ func (c *client) useToken(token string) {
 	c.updateAccessToken()
 }
 
-func (c *client) login(user string, password string) error {
+func (c *client) login(ctx context.Context, user string, password string) error {
 	c.apiURL.RawQuery = ""
 	defer c.updateAccessToken()
 
 	resLogin := apiResLoginFlows{}
-	if err := c.apiGet(apiLogin, &resLogin); err != nil {
+	if err := c.apiGet(ctx, apiLogin, &resLogin); err != nil {
 		return fmt.Errorf("failed to get login flows: %w", err)
 	}
 
@@ -60,16 +64,16 @@ func (c *client) login(user string, password string) error {
 		flows = append(flows, string(flow.Type))
 		if flow.Type == flowLoginPassword {
 			c.logf("Using login flow '%v'", flow.Type)
-			return c.loginPassword(user, password)
+			return c.loginPassword(ctx, user, password)
 		}
 	}
 
 	return fmt.Errorf("none of the server login flows are supported: %v", strings.Join(flows, ", "))
 }
 
-func (c *client) loginPassword(user string, password string) error {
+func (c *client) loginPassword(ctx context.Context, user string, password string) error {
 	response := apiResLogin{}
-	if err := c.apiPost(apiLogin, apiReqLogin{
+	if err := c.apiPost(ctx, apiLogin, apiReqLogin{
 		Type:       flowLoginPassword,
 		Password:   password,
 		Identifier: newUserIdentifier(user),
@@ -90,21 +94,23 @@ func (c *client) loginPassword(user string, password string) error {
 	return nil
 }
 
-func (c *client) sendMessage(message string, rooms []string) (errors []error) {
+// sendMessage sends the message to the rooms, or all joined rooms if none are given, returning the number of rooms that
+// it was sent to and the errors for the others
+func (c *client) sendMessage(ctx context.Context, message apiReqSend, rooms []string) (sent int, errors []error) {
 	if len(rooms) > 0 {
-		return c.sendToExplicitRooms(rooms, message)
+		return c.sendToExplicitRooms(ctx, rooms, message)
 	}
-	return c.sendToJoinedRooms(message)
+	return c.sendToJoinedRooms(ctx, message)
 }
 
-func (c *client) sendToExplicitRooms(rooms []string, message string) (errors []error) {
+func (c *client) sendToExplicitRooms(ctx context.Context, rooms []string, message apiReqSend) (sent int, errors []error) {
 	var err error
 
 	for _, room := range rooms {
 		c.logf("Sending message to '%v'...\n", room)
 
 		var roomID string
-		if roomID, err = c.joinRoom(room); err != nil {
+		if roomID, err = c.joinRoom(ctx, room); err != nil {
 			errors = append(errors, fmt.Errorf("error joining room %v: %w", roomID, err))
 			continue
 		}
@@ -113,96 +119,129 @@ func (c *client) sendToExplicitRooms(rooms []string, message string) (errors []e
 			c.logf("Resolved room alias '%v' to ID '%v'", room, roomID)
 		}
 
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
+		if err := c.sendMessageToRoom(ctx, message, roomID); err != nil {
 			errors = append(errors, fmt.Errorf("failed to send message to room '%v': %w", roomID, err))
+			continue
 		}
+		sent++
 	}
 
-	return errors
+	return sent, errors
 }
 
-func (c *client) sendToJoinedRooms(message string) (errors []error) {
-	joinedRooms, err := c.getJoinedRooms()
+func (c *client) sendToJoinedRooms(ctx context.Context, message apiReqSend) (sent int, errors []error) {
+	joinedRooms, err := c.getJoinedRooms(ctx)
 	if err != nil {
-		return append(errors, fmt.Errorf("failed to get joined rooms: %w", err))
+		return 0, append(errors, fmt.Errorf("failed to get joined rooms: %w", err))
 	}
 
 	// Send to all rooms that are joined
 	for _, roomID := range joinedRooms {
 		c.logf("Sending message to '%v'...\n", roomID)
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
+		if err := c.sendMessageToRoom(ctx, message, roomID); err != nil {
 			errors = append(errors, fmt.Errorf("failed to send message to room '%v': %w", roomID, err))
+			continue
 		}
+		sent++
+	}
+
+	return sent, errors
+}
+
+// uploadMedia uploads the file to the content repository of the server, returning a message that links to it
+func (c *client) uploadMedia(ctx context.Context, file types.Attachment) (apiReqSend, error) {
+	c.logf("Uploading '%v'...\n", file.Name)
+
+	resUpload := apiResUpload{}
+	if err := c.apiUpload(ctx, file, &resUpload); err != nil {
+		return apiReqSend{}, fmt.Errorf("failed to upload %q: %w", file.Name, err)
+	}
+
+	msgType := msgTypeFile
+	if file.IsImage() {
+		msgType = msgTypeImage
 	}
 
-	return errors
+	return apiReqSend{
+		MsgType: msgType,
+		Body:    file.Name,
+		URL:     resUpload.ContentURI,
+		Info: &mediaInfo{
+			MimeType: file.MediaType(),
+			Size:     len(file.Content),
+		},
+	}, nil
 }
 
-func (c *client) joinRoom(room string) (roomID string, err error) {
+func (c *client) joinRoom(ctx context.Context, room string) (roomID string, err error) {
 	resRoom := apiResRoom{}
-	if err = c.apiPost(fmt.Sprintf(apiRoomJoin, room), nil, &resRoom); err != nil {
+	if err = c.apiPost(ctx, fmt.Sprintf(apiRoomJoin, room), nil, &resRoom); err != nil {
 		return "", err
 	}
 	return resRoom.RoomID, nil
 }
 
-func (c *client) sendMessageToRoom(message string, roomID string) error {
+func (c *client) sendMessageToRoom(ctx context.Context, message apiReqSend, roomID string) error {
 	resEvent := apiResEvent{}
-	return c.apiPost(fmt.Sprintf(apiSendMessage, roomID), apiReqSend{
-		MsgType: msgTypeText,
-		Body:    message,
-	}, &resEvent)
+	if err := c.apiPost(ctx, fmt.Sprintf(apiSendMessage, roomID), message, &resEvent); err != nil {
+		return err
+	}
+	types.ReportMessageID(ctx, resEvent.EventID)
+	return nil
 }
 
-func (c *client) apiGet(path string, response interface{}) error {
+func (c *client) apiGet(ctx context.Context, path string, response interface{}) error {
 	c.apiURL.Path = path
 
-	var err error
-	var res *http.Response
-	res, err = http.Get(c.apiURL.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL.String(), nil)
 	if err != nil {
 		return err
 	}
 
-	var body []byte
-	defer res.Body.Close()
-	body, err = ioutil.ReadAll(res.Body)
+	return c.apiDo(req, response)
+}
 
-	if res.StatusCode >= 400 {
-		resError := &apiResError{}
-		if err == nil {
-			if err = json.Unmarshal(body, resError); err == nil {
-				return resError
-			}
-		}
+func (c *client) apiPost(ctx context.Context, path string, request interface{}, response interface{}) error {
+	c.apiURL.Path = path
 
-		return fmt.Errorf("got HTTP %v", res.Status)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
 	}
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL.String(), bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", contentType)
 
-	return json.Unmarshal(body, response)
+	return c.apiDo(req, response)
 }
 
-func (c *client) apiPost(path string, request interface{}, response interface{}) error {
-	c.apiURL.Path = path
-
-	var err error
-	var body []byte
+func (c *client) apiUpload(ctx context.Context, file types.Attachment, response interface{}) error {
+	uploadURL := c.apiURL
+	uploadURL.Path = apiUploadMedia
+	query := uploadURL.Query()
+	query.Set("filename", file.Name)
+	uploadURL.RawQuery = query.Encode()
 
-	body, err = json.Marshal(request)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL.String(), bytes.NewReader(file.Content))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", file.MediaType())
+
+	return c.apiDo(req, response)
+}
 
-	var res *http.Response
-	res, err = http.Post(c.apiURL.String(), contentType, bytes.NewReader(body))
+// apiDo sends the request, unmarshalling the response body into response, or returning the API error if it failed
+func (c *client) apiDo(req *http.Request, response interface{}) error {
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 
+	var body []byte
 	defer res.Body.Close()
 	body, err = ioutil.ReadAll(res.Body)
 
@@ -210,11 +249,11 @@ func (c *client) apiPost(path string, request interface{}, response interface{})
 		resError := &apiResError{}
 		if err == nil {
 			if err = json.Unmarshal(body, resError); err == nil {
-				return resError
+				return retry.NewResponseError(res, resError)
 			}
 		}
 
-		return fmt.Errorf("got HTTP %v", res.Status)
+		return retry.NewResponseError(res, fmt.Errorf("got HTTP %v", res.Status))
 	}
 
 	if err != nil {
@@ -234,9 +273,9 @@ func (c *client) logf(format string, v ...interface{}) {
 	c.logger.Printf(format, v...)
 }
 
-func (c *client) getJoinedRooms() ([]string, error) {
+func (c *client) getJoinedRooms(ctx context.Context) ([]string, error) {
 	response := apiResJoinedRooms{}
-	if err := c.apiGet(apiJoinedRooms, &response); err != nil {
+	if err := c.apiGet(ctx, apiJoinedRooms, &response); err != nil {
 		return []string{}, err
 	}
 	return response.Rooms, nil
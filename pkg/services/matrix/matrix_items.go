@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// levelColors are the font colors used for the message item levels in the formatted body
+var levelColors = [t.MessageLevelCount]string{
+	t.Debug:   "#7b00ab",
+	t.Info:    "#2488ff",
+	t.Warning: "#ffc441",
+	t.Error:   "#d60510",
+}
+
+// createItemsMessage creates a message with a HTML formatted body from the message items, together with a plain text
+// body for clients that does not support formatting
+func createItemsMessage(items []t.MessageItem, title string) apiReqSend {
+	plain := strings.Builder{}
+	formatted := strings.Builder{}
+
+	if title != "" {
+		plain.WriteString(title + "\n")
+		formatted.WriteString("<h4>" + html.EscapeString(title) + "</h4>")
+	}
+
+	for _, item := range items {
+		var header []string
+		var formattedHeader []string
+		if item.Level != t.Unknown && item.Level < t.MessageLevel(t.MessageLevelCount) {
+			header = append(header, "["+item.Level.String()+"]")
+			formattedHeader = append(formattedHeader, fmt.Sprintf(`<font color="%s"><strong>%s</strong></font>`,
+				levelColors[item.Level], item.Level.String()))
+		}
+		if !item.Timestamp.IsZero() {
+			timestamp := item.Timestamp.UTC().Format(time.RFC3339)
+			header = append(header, timestamp)
+			formattedHeader = append(formattedHeader, "<em>"+timestamp+"</em>")
+		}
+
+		if len(header) > 0 {
+			plain.WriteString(strings.Join(header, " ") + " ")
+		}
+		plain.WriteString(item.Text + "\n")
+
+		formatted.WriteString("<p>")
+		if len(formattedHeader) > 0 {
+			formatted.WriteString(strings.Join(formattedHeader, " ") + "<br>")
+		}
+		formatted.WriteString(strings.ReplaceAll(html.EscapeString(item.Text), "\n", "<br>"))
+		formatted.WriteString("</p>")
+
+		if len(item.Fields) > 0 {
+			formatted.WriteString("<ul>")
+			for _, field := range item.Fields {
+				plain.WriteString(fmt.Sprintf("%s: %s\n", field.Key, field.Value))
+				formatted.WriteString(fmt.Sprintf("<li><strong>%s</strong>: %s</li>",
+					html.EscapeString(field.Key), html.EscapeString(field.Value)))
+			}
+			formatted.WriteString("</ul>")
+		}
+	}
+
+	return apiReqSend{
+		MsgType:       msgTypeText,
+		Body:          strings.TrimSuffix(plain.String(), "\n"),
+		Format:        formatHTML,
+		FormattedBody: formatted.String(),
+	}
+}
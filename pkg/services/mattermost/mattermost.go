@@ -2,6 +2,7 @@ package mattermost
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -29,23 +31,65 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to Mattermost
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	apiURL := buildURL(config)
+	return service.SendContext(context.Background(), message, params)
+}
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+// SendContext sends a notification message to Mattermost, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	req, err := service.buildRequest(ctx, &config, message, params)
+	if err != nil {
 		return err
 	}
-	json, _ := CreateJSONPayload(config, message, params)
-	res, err := http.Post(apiURL, "application/json", bytes.NewReader(json))
+
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to service, response status code %s", res.Status)
+		return retry.NewResponseError(res, fmt.Errorf("failed to send notification to service, response status code %s", res.Status))
 	}
 	return err
 }
 
+// SendMarkdownContext sends the Markdown message to Mattermost, which renders it natively, aborting the request if
+// ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.SendContext(ctx, message, params)
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	req, err := service.buildRequest(ctx, &config, message, params)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.BuildRequests(ctx, message, params)
+}
+
+// buildRequest returns the webhook request for the message, updating config from the params
+func (service *Service) buildRequest(ctx context.Context, config *Config, message string, params *types.Params) (*http.Request, error) {
+	apiURL := buildURL(config)
+
+	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+		return nil, err
+	}
+	json, _ := CreateJSONPayload(config, message, params)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(json))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
 // Builds the actual URL the request should go to
 func buildURL(config *Config) string {
 	return fmt.Sprintf("https://%s/hooks/%s", config.Host, config.Token)
@@ -18,7 +18,7 @@ type Config struct {
 	Title    string `key:"title" default:"" desc:"Notification title, optionally set by the sender (not used)"`
 	Channel  string `url:"path2" optional:"" desc:"Override webhook channel"`
 	Host     string `url:"host,port" desc:"Mattermost server host"`
-	Token    string `url:"path1" desc:"Webhook token"`
+	Token    string `url:"path1" desc:"Webhook token" secret:""`
 }
 
 // GetURL returns a URL representation of it's current field values
@@ -2,6 +2,8 @@
 package ntfy
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +12,7 @@ import (
 	"github.com/containrrr/shoutrrr/internal/meta"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -24,19 +27,64 @@ type Service struct {
 
 // Send a notification message to Ntfy
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Ntfy, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	if err := service.sendAPI(ctx, &config, message, false); err != nil {
+		return fmt.Errorf("failed to send ntfy notification: %w", err)
+	}
+
+	return nil
+}
+
+// SendMarkdownContext sends the Markdown message to Ntfy with Markdown formatting enabled, aborting the request if ctx
+// is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	if err := service.sendAPI(config, message); err != nil {
+	if err := service.sendAPI(ctx, &config, message, true); err != nil {
 		return fmt.Errorf("failed to send ntfy notification: %w", err)
 	}
 
 	return nil
 }
 
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.buildRequests(ctx, message, params, false)
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.buildRequests(ctx, message, params, true)
+}
+
+func (service *Service) buildRequests(ctx context.Context, message string, params *types.Params, markdown bool) ([]*http.Request, error) {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	req, err := service.buildAPIRequest(ctx, &config, message, markdown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ntfy request: %w", err)
+	}
+	return []*http.Request{req}, nil
+}
+
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
@@ -49,10 +97,99 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 }
 
-func (service *Service) sendAPI(config *Config, message string) error {
+// SendItems sends the message items to Ntfy, uploading any attachments of the items
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the text of the message items to Ntfy, aborting the request if ctx is done. Since Ntfy only
+// allows one attachment per notification, the text is sent together with the first attachment, and a separate
+// notification is sent for each of the others.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
+		return err
+	}
+
+	message := strings.TrimSuffix(types.ItemsToPlain(items), "\n")
+	files := types.ItemsAttachments(items)
+
+	var err error
+	if len(files) == 0 {
+		err = service.sendAPI(ctx, &config, message, false)
+	}
+	for i, file := range files {
+		if i > 0 {
+			message = ""
+		}
+		if err = service.sendFile(ctx, &config, message, file); err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to send ntfy notification: %w", err)
+	}
+
+	return nil
+}
+
+func (service *Service) sendAPI(ctx context.Context, config *Config, message string, markdown bool) error {
+	req, err := service.buildAPIRequest(ctx, config, message, markdown)
+	if err != nil {
+		return err
+	}
+
+	response := apiResponse{}
+	jsonClient := newClient(service.GetHTTPClient(), config)
+	if err := jsonClient.Do(req, &response); err != nil {
+		return apiError(jsonClient, err, &response)
+	}
+
+	types.ReportMessageID(ctx, response.ID)
+	return nil
+}
+
+// buildAPIRequest returns the request for publishing the message, with the headers of the config set
+func (service *Service) buildAPIRequest(ctx context.Context, config *Config, message string, markdown bool) (*http.Request, error) {
+	jsonClient := newClient(service.GetHTTPClient(), config)
+	if markdown {
+		jsonClient.Headers().Set("Markdown", "yes")
+	}
+
+	return jsonClient.NewRequest(ctx, http.MethodPost, config.GetAPIURL(), message)
+}
+
+// sendFile uploads the file as the attachment of a notification, using the message as its text. If the message is
+// empty, the server will use the file name instead.
+func (service *Service) sendFile(ctx context.Context, config *Config, message string, file types.Attachment) error {
 	response := apiResponse{}
-	request := message
-	jsonClient := jsonclient.NewClient()
+	jsonClient := newClient(service.GetHTTPClient(), config)
+
+	apiURL := config.GetAPIURL()
+	if message != "" {
+		// The message is passed as a query param, since the body contains the file
+		apiURL += "?" + url.Values{"message": []string{message}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, apiURL, bytes.NewReader(file.Content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Filename", file.Name)
+
+	if err := jsonClient.Do(req, &response); err != nil {
+		return apiError(jsonClient, err, &response)
+	}
+
+	types.ReportMessageID(ctx, response.ID)
+	return nil
+}
+
+// newClient returns a client with the headers of the config set as its default headers
+func newClient(httpClient *http.Client, config *Config) jsonclient.Client {
+	jsonClient := jsonclient.NewWithHTTPClient(httpClient)
 
 	headers := jsonClient.Headers()
 	headers.Del("Content-Type")
@@ -75,15 +212,16 @@ func (service *Service) sendAPI(config *Config, message string) error {
 		headers.Add("Firebase", "no")
 	}
 
-	if err := jsonClient.Post(config.GetAPIURL(), request, &response); err != nil {
-		if jsonClient.ErrorResponse(err, &response) {
-			// apiResponse implements Error
-			return &response
-		}
-		return err
-	}
+	return jsonClient
+}
 
-	return nil
+// apiError returns the error from the API response, if the failed request had one
+func apiError(jsonClient jsonclient.Client, err error, response *apiResponse) error {
+	if jsonClient.ErrorResponse(err, response) {
+		// apiResponse implements Error
+		return retry.WithStatusOf(response, err)
+	}
+	return err
 }
 
 func addHeaderIfNotEmpty(headers *http.Header, key string, value string) {
@@ -3,6 +3,7 @@ package ntfy
 import "fmt"
 
 type apiResponse struct {
+	ID      string `json:"id"`
 	Code    int64  `json:"code"`
 	Message string `json:"error"`
 	Link    string `json:"link"`
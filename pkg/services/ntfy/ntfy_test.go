@@ -3,11 +3,15 @@ package ntfy
 import (
 	"github.com/containrrr/shoutrrr/internal/testutils"
 	"github.com/containrrr/shoutrrr/pkg/format"
+	"github.com/containrrr/shoutrrr/pkg/types"
 
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -115,6 +119,75 @@ var _ = Describe("the ntfy service", func() {
 
 			Expect(service.Send("Message", nil)).To(HaveOccurred())
 		})
+		It("should enable Markdown formatting for Markdown messages", func() {
+			serviceURL := testutils.URLMust("ntfy://:devicekey@hostname")
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			var markdown []string
+			httpmock.RegisterResponder("POST", service.config.GetAPIURL(), func(req *http.Request) (*http.Response, error) {
+				markdown = append(markdown, req.Header.Get("Markdown"))
+				return testutils.JSONRespondMust(200, apiResponse{Code: http.StatusOK, Message: "OK"})(req)
+			})
+
+			Expect(service.SendMarkdownContext(context.Background(), "**Message**", nil)).To(Succeed())
+			Expect(service.Send("Message", nil)).To(Succeed())
+			Expect(markdown).To(Equal([]string{"yes", ""}))
+		})
+		It("should only use the params for the send they were passed to, even when sending concurrently", func() {
+			serviceURL := testutils.URLMust("ntfy://:devicekey@hostname")
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			lock := sync.Mutex{}
+			var titles []string
+			httpmock.RegisterResponder("POST", service.config.GetAPIURL(), func(req *http.Request) (*http.Response, error) {
+				lock.Lock()
+				titles = append(titles, req.Header.Get("Title"))
+				lock.Unlock()
+				return testutils.JSONRespondMust(200, apiResponse{Code: http.StatusOK, Message: "OK"})(req)
+			})
+
+			Expect(service.Send("Message", &types.Params{"title": "first"})).To(Succeed())
+			Expect(service.Send("Message", nil)).To(Succeed())
+			Expect(titles).To(Equal([]string{"first", ""}))
+
+			titles = nil
+			expected := make([]string, 10)
+			wg := sync.WaitGroup{}
+			for i := range expected {
+				expected[i] = fmt.Sprintf("title %d", i)
+				wg.Add(1)
+				go func(title string) {
+					defer wg.Done()
+					defer GinkgoRecover()
+					Expect(service.Send("Message", &types.Params{"title": title})).To(Succeed())
+				}(expected[i])
+			}
+			wg.Wait()
+			Expect(titles).To(ConsistOf(expected))
+		})
+		It("should upload each attachment using a separate request", func() {
+			serviceURL := testutils.URLMust("ntfy://:devicekey@hostname")
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			var filenames []string
+			var messages []string
+			httpmock.RegisterResponder("PUT", `=~^`+service.config.GetAPIURL(), func(req *http.Request) (*http.Response, error) {
+				filenames = append(filenames, req.Header.Get("Filename"))
+				messages = append(messages, req.URL.Query().Get("message"))
+				return testutils.JSONRespondMust(200, apiResponse{Code: http.StatusOK, Message: "OK"})(req)
+			})
+
+			items := []types.MessageItem{{
+				Text: "Message",
+				Attachments: []types.Attachment{
+					types.NewAttachment("first.log", []byte("first")),
+					types.NewAttachment("second.log", []byte("second")),
+				},
+			}}
+			Expect(service.SendItems(items, nil)).To(Succeed())
+			Expect(filenames).To(Equal([]string{"first.log", "second.log"}))
+			Expect(messages).To(Equal([]string{"Message", ""}))
+		})
 		It("should not panic if a communication error occurs", func() {
 			httpmock.DeactivateAndReset()
 			serviceURL := testutils.URLMust("ntfy://:devicekey@nonresolvablehostname")
@@ -2,6 +2,7 @@ package opsgenie
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 const (
@@ -24,37 +26,46 @@ type Service struct {
 	pkr    format.PropKeyResolver
 }
 
-func (service *Service) sendAlert(url string, apiKey string, payload AlertPayload) error {
-	jsonBody, err := json.Marshal(payload)
+func (service *Service) sendAlert(ctx context.Context, url string, apiKey string, payload AlertPayload) error {
+	req, err := buildAlertRequest(ctx, url, apiKey, payload)
 	if err != nil {
 		return err
 	}
 
-	jsonBuffer := bytes.NewBuffer(jsonBody)
-
-	req, err := http.NewRequest("POST", url, jsonBuffer)
-	if err != nil {
-		return err
-	}
-	req.Header.Add("Authorization", "GenieKey "+apiKey)
-	req.Header.Add("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := service.GetHTTPClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send notification to OpsGenie: %s", err)
+		return fmt.Errorf("failed to send notification to OpsGenie: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return fmt.Errorf("OpsGenie notification returned %d HTTP status code. Cannot read body: %s", resp.StatusCode, err)
+			return retry.NewResponseError(resp, fmt.Errorf("OpsGenie notification returned %d HTTP status code. Cannot read body: %s", resp.StatusCode, err))
 		}
-		return fmt.Errorf("OpsGenie notification returned %d HTTP status code: %s", resp.StatusCode, body)
+		return retry.NewResponseError(resp, fmt.Errorf("OpsGenie notification returned %d HTTP status code: %s", resp.StatusCode, body))
 	}
 
 	return nil
 }
 
+func buildAlertRequest(ctx context.Context, url string, apiKey string, payload AlertPayload) (*http.Request, error) {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBuffer := bytes.NewBuffer(jsonBody)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, jsonBuffer)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "GenieKey "+apiKey)
+	req.Header.Add("Content-Type", "application/json")
+	return req, nil
+}
+
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
@@ -66,13 +77,34 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 // Send a notification message to OpsGenie
 // See: https://docs.opsgenie.com/docs/alert-api#create-alert
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to OpsGenie, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 	endpointURL := fmt.Sprintf(alertEndpointTemplate, config.Host, config.Port)
 	payload, err := service.newAlertPayload(message, params)
 	if err != nil {
 		return err
 	}
-	return service.sendAlert(endpointURL, config.APIKey, payload)
+	return service.sendAlert(ctx, endpointURL, config.APIKey, payload)
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	endpointURL := fmt.Sprintf(alertEndpointTemplate, config.Host, config.Port)
+	payload, err := service.newAlertPayload(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := buildAlertRequest(ctx, endpointURL, config.APIKey, payload)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
 }
 
 func (service *Service) newAlertPayload(message string, params *types.Params) (AlertPayload, error) {
@@ -1,11 +1,14 @@
 package pushbullet
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+	"net/http"
 	"net/url"
 )
 
@@ -16,7 +19,6 @@ const (
 // Service providing Pushbullet as a notification service
 type Service struct {
 	standard.Standard
-	client jsonclient.Client
 	config *Config
 	pkr    format.PropKeyResolver
 }
@@ -31,42 +33,85 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 		return err
 	}
 
-	service.client = jsonclient.NewClient()
-	service.client.Headers().Set("Access-Token", service.config.Token)
-
 	return nil
 }
 
 // Send a push notification via Pushbullet
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a push notification via Pushbullet, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	config := *service.config
 	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	for _, target := range config.Targets {
-		if err := doSend(&config, target, message, service.client); err != nil {
+	client := newClient(service.GetHTTPClient(), &config)
+
+	for i, target := range config.Targets {
+		if err := doSend(ctx, &config, target, message, client); err != nil {
+			if i > 0 {
+				// The message has already been pushed to the previous targets
+				return retry.Partial(err)
+			}
 			return err
 		}
 	}
 	return nil
 }
 
-func doSend(config *Config, target string, message string, client jsonclient.Client) error {
+// BuildRequests returns the requests that SendContext would make for the message, one for each target, without
+// sending them
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	client := newClient(service.GetHTTPClient(), &config)
+
+	requests := make([]*http.Request, 0, len(config.Targets))
+	for _, target := range config.Targets {
+		req, err := buildPushRequest(ctx, &config, target, message, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build push request: %w", err)
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// newClient returns a client that authenticates using the access token of the config
+func newClient(httpClient *http.Client, config *Config) jsonclient.Client {
+	client := jsonclient.NewWithHTTPClient(httpClient)
+	client.Headers().Set("Access-Token", config.Token)
+	return client
+}
 
+func buildPushRequest(ctx context.Context, config *Config, target string, message string, client jsonclient.Client) (*http.Request, error) {
 	push := NewNotePush(message, config.Title)
 	push.SetTarget(target)
 
+	return client.NewRequest(ctx, http.MethodPost, pushesEndpoint, push)
+}
+
+func doSend(ctx context.Context, config *Config, target string, message string, client jsonclient.Client) error {
+	req, err := buildPushRequest(ctx, config, target, message, client)
+	if err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
 	response := PushResponse{}
-	if err := client.Post(pushesEndpoint, push, &response); err != nil {
+	if err := client.Do(req, &response); err != nil {
 		errorResponse := &ErrorResponse{}
 		if client.ErrorResponse(err, errorResponse) {
-			return fmt.Errorf("API error: %w", errorResponse)
+			return retry.WithStatusOf(fmt.Errorf("API error: %w", errorResponse), err)
 		}
 		return fmt.Errorf("failed to push: %w", err)
 	}
 
-	// TODO: Look at response fields?
-
+	types.ReportMessageID(ctx, response.Iden)
 	return nil
 }
@@ -1,15 +1,21 @@
 package pushover
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 const (
@@ -26,20 +32,134 @@ type Service struct {
 
 // Send a notification message to Pushover
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Pushover, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	device := strings.Join(config.Devices, ",")
+	if err := service.sendToDevice(ctx, device, message, false, &config, nil); err != nil {
+		return fmt.Errorf("failed to send notifications to pushover devices: %w", err)
+	}
+
+	return nil
+}
+
+// SendMarkdownContext converts the Markdown message to the HTML supported by Pushover and sends it, aborting the
+// request if ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	device := strings.Join(config.Devices, ",")
+	message = markdown.ToLimitedHTML(message, markdown.PushoverTags)
+	if err := service.sendToDevice(ctx, device, message, true, &config, nil); err != nil {
+		return fmt.Errorf("failed to send notifications to pushover devices: %w", err)
+	}
+
+	return nil
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.buildRequests(ctx, message, false, params)
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.buildRequests(ctx, markdown.ToLimitedHTML(message, markdown.PushoverTags), true, params)
+}
+
+func (service *Service) buildRequests(ctx context.Context, message string, html bool, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	device := strings.Join(config.Devices, ",")
+	req, err := buildRequest(ctx, device, message, html, &config, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pushover request: %w", err)
+	}
+	return []*http.Request{req}, nil
+}
+
+// SendItems sends the message items to Pushover, attaching any images of the items
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the text of the message items to Pushover, aborting the request if ctx is done. Since
+// Pushover only allows a single image per notification, the text is sent together with the first image, and a separate
+// notification is sent for each of the others, using the file name as the message. Attachments other than images are
+// not supported.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
 		return err
 	}
 
+	images := types.ItemsAttachments(items)
+	for _, image := range images {
+		if !image.IsImage() {
+			return fmt.Errorf("failed to send notifications to pushover devices: %w for %q, only images can be attached",
+				types.ErrAttachmentsUnsupported, image.Name)
+		}
+	}
+
+	message := strings.TrimSuffix(types.ItemsToPlain(items), "\n")
 	device := strings.Join(config.Devices, ",")
-	if err := service.sendToDevice(device, message, config); err != nil {
+
+	var err error
+	if len(images) == 0 {
+		err = service.sendToDevice(ctx, device, message, false, &config, nil)
+	}
+	for i, image := range images {
+		if i > 0 || message == "" {
+			message = image.Name
+		}
+		if err = service.sendToDevice(ctx, device, message, false, &config, &images[i]); err != nil {
+			break
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("failed to send notifications to pushover devices: %w", err)
 	}
 
 	return nil
 }
 
-func (service *Service) sendToDevice(device string, message string, config *Config) error {
+// sendToDevice sends the message to the device, where html sets whether the message contains the HTML tags supported by
+// Pushover
+func (service *Service) sendToDevice(ctx context.Context, device string, message string, html bool, config *Config, image *types.Attachment) error {
+	req, err := buildRequest(ctx, device, message, html, config, image)
+	if err != nil {
+		return err
+	}
+
+	res, err := service.GetHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return retry.NewResponseError(res, fmt.Errorf("failed to send notification to pushover device %q, response status %q", device, res.Status))
+	}
+
+	return nil
+}
+
+// buildRequest returns the request for sending the message to the device, with the image attached if it is not nil
+func buildRequest(ctx context.Context, device string, message string, html bool, config *Config, image *types.Attachment) (*http.Request, error) {
 
 	data := url.Values{}
 	data.Set("device", device)
@@ -47,6 +167,10 @@ func (service *Service) sendToDevice(device string, message string, config *Conf
 	data.Set("token", config.Token)
 	data.Set("message", message)
 
+	if html {
+		data.Set("html", "1")
+	}
+
 	if len(config.Title) > 0 {
 		data.Set("title", config.Title)
 	}
@@ -55,20 +179,52 @@ func (service *Service) sendToDevice(device string, message string, config *Conf
 		data.Set("priority", strconv.FormatInt(int64(config.Priority), 10))
 	}
 
-	res, err := http.Post(
-		hookURL,
-		contentType,
-		strings.NewReader(data.Encode()))
+	body, bodyType := []byte(data.Encode()), contentType
+	if image != nil {
+		var err error
+		if body, bodyType, err = createMultipartBody(data, image); err != nil {
+			return nil, err
+		}
+	}
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	req.Header.Set("Content-Type", bodyType)
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to pushover device %q, response status %q", device, res.Status)
+	return req, nil
+}
+
+// createMultipartBody creates a multipart/form-data request body with the values and the image as the attachment,
+// returning the body and its content type
+func createMultipartBody(data url.Values, image *types.Attachment) ([]byte, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key := range data {
+		if err := writer.WriteField(key, data.Get(key)); err != nil {
+			return nil, "", err
+		}
 	}
 
-	return nil
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename=%q`, image.Name))
+	header.Set("Content-Type", image.MediaType())
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(image.Content); err != nil {
+		return nil, "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -3,6 +3,7 @@ package pushover_test
 import (
 	"errors"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/containrrr/shoutrrr/internal/testutils"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/pushover"
+	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/jarcoal/httpmock"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -173,6 +175,41 @@ var _ = Describe("the pushover config", func() {
 			err = service.Send("Message", nil)
 			Expect(err).To(HaveOccurred())
 		})
+		It("should send images as the attachment of the notification", func() {
+			serviceURL, err := url.Parse("pushover://:apptoken@usertoken")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			var message, filename string
+			httpmock.RegisterResponder("POST", hookURL, func(req *http.Request) (*http.Response, error) {
+				message = req.FormValue("message")
+				if _, header, err := req.FormFile("attachment"); err == nil {
+					filename = header.Filename
+				}
+				return httpmock.NewStringResponse(200, ""), nil
+			})
+
+			items := []types.MessageItem{{
+				Text:        "Message",
+				Attachments: []types.Attachment{types.NewAttachment("screenshot.png", []byte("png"))},
+			}}
+			Expect(service.SendItems(items, nil)).To(Succeed())
+			Expect(message).To(Equal("Message"))
+			Expect(filename).To(Equal("screenshot.png"))
+		})
+		It("should report that attachments other than images are unsupported", func() {
+			serviceURL, err := url.Parse("pushover://:apptoken@usertoken")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			items := []types.MessageItem{{
+				Text:        "Message",
+				Attachments: []types.Attachment{types.NewAttachment("server.log", []byte("log"))},
+			}}
+			err = service.SendItems(items, nil)
+			Expect(errors.Is(err, types.ErrAttachmentsUnsupported)).To(BeTrue())
+			Expect(httpmock.GetTotalCallCount()).To(BeZero())
+		})
 	})
 })
 
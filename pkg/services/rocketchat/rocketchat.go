@@ -2,6 +2,7 @@ package rocketchat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -30,23 +32,60 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to Rocket.chat
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Rocket.chat, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	var res *http.Response
-	var err error
-	config := service.config
-	apiURL := buildURL(config)
-	json, _ := CreateJSONPayload(config, message, params)
-	res, err = http.Post(apiURL, "application/json", bytes.NewReader(json))
+	config := *service.config
+	req, err := buildRequest(ctx, &config, message, params)
+	if err != nil {
+		return err
+	}
+
+	res, err = service.GetHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("Error while posting to URL: %w\nHOST: %s\nPORT: %s", err, config.Host, config.Port)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
 		resBody, _ := ioutil.ReadAll(res.Body)
-		return fmt.Errorf("notification failed: %d %s", res.StatusCode, resBody)
+		return retry.NewResponseError(res, fmt.Errorf("notification failed: %d %s", res.StatusCode, resBody))
 	}
 	return err
 }
 
+// SendMarkdownContext sends the Markdown message to Rocket.chat, which renders it natively, aborting the request if
+// ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.SendContext(ctx, message, params)
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	req, err := buildRequest(ctx, service.config, message, params)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.BuildRequests(ctx, message, params)
+}
+
+func buildRequest(ctx context.Context, config *Config, message string, params *types.Params) (*http.Request, error) {
+	json, _ := CreateJSONPayload(config, message, params)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, buildURL(config), bytes.NewReader(json))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
 func buildURL(config *Config) string {
 	if config.Port != "" {
 		return fmt.Sprintf("https://%s:%s/hooks/%s/%s", config.Host, config.Port, config.TokenA, config.TokenB)
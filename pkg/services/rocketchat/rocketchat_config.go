@@ -16,9 +16,9 @@ type Config struct {
 	UserName string `url:"user" optional:""`
 	Host     string `url:"host"`
 	Port     string `url:"port"`
-	TokenA   string `url:"path1"`
+	TokenA   string `url:"path1" secret:""`
 	Channel  string `url:"path3"`
-	TokenB   string `url:"path2"`
+	TokenB   string `url:"path2" secret:""`
 }
 
 // GetURL returns a URL representation of it's current field values
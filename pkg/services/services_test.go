@@ -20,14 +20,17 @@ func TestServices(t *testing.T) {
 }
 
 var serviceURLs = map[string]string{
+	"bark":       "bark://:devicekey@example.com",
 	"discord":    "discord://token@id",
 	"gotify":     "gotify://example.com/Aaa.bbb.ccc.ddd",
+	"generic":    "generic://example.com",
 	"googlechat": "googlechat://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz",
 	"hangouts":   "hangouts://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz",
 	"ifttt":      "ifttt://key?events=event",
 	"join":       "join://:apikey@join/?devices=device",
 	"logger":     "logger://",
 	"mattermost": "mattermost://user@example.com/token",
+	"ntfy":       "ntfy://ntfy.sh/topic",
 	"opsgenie":   "opsgenie://example.com/token?responders=user:dummy",
 	"pushbullet": "pushbullet://tokentokentokentokentokentokentoke",
 	"pushover":   "pushover://:token@user/?devices=device",
@@ -41,6 +44,8 @@ var serviceURLs = map[string]string{
 }
 
 var serviceResponses = map[string]string{
+	"bark":       `{"code": 200}`,
+	"ntfy":       `{"id": "id"}`,
 	"pushbullet": `{"created": 0}`,
 	"gotify":     `{"id": 0}`,
 }
@@ -56,6 +61,19 @@ var _ = Describe("services", func() {
 
 	})
 
+	When("sending with a context", func() {
+		serviceRouter, _ := router.New(logger)
+		for _, scheme := range serviceRouter.ListServices() {
+			scheme := scheme
+			It("should be natively supported by "+scheme, func() {
+				service, err := serviceRouter.NewService(scheme)
+				Expect(err).NotTo(HaveOccurred())
+				_, isContextSender := service.(types.ContextSender)
+				Expect(isContextSender).To(BeTrue())
+			})
+		}
+	})
+
 	When("passed the a title param", func() {
 
 		var serviceRouter *router.ServiceRouter
@@ -103,4 +121,42 @@ var _ = Describe("services", func() {
 		}
 	})
 
+	When("the router has a HTTP client", func() {
+
+		AfterEach(func() {
+			httpmock.DeactivateAndReset()
+		})
+
+		for key, configURL := range serviceURLs {
+
+			key := key
+			configURL := configURL
+
+			It("should be used for the requests of "+key, func() {
+
+				if key == "smtp" || key == "xmpp" || key == "logger" {
+					Skip("does not use HTTP")
+				}
+
+				// Any request using the default transport fails, since it has no responders
+				httpmock.Activate()
+
+				respStatus := http.StatusOK
+				if key == "discord" || key == "ifttt" {
+					respStatus = http.StatusNoContent
+				}
+				transport := httpmock.NewMockTransport()
+				transport.RegisterNoResponder(httpmock.NewStringResponder(respStatus, serviceResponses[key]))
+
+				serviceRouter, err := router.New(logger)
+				Expect(err).NotTo(HaveOccurred())
+				serviceRouter.SetHTTPClient(&http.Client{Transport: transport})
+
+				Expect(serviceRouter.AddService(configURL)).To(Succeed())
+				Expect(serviceRouter.Send("test", nil)).To(Equal([]error{nil}))
+				Expect(transport.GetTotalCallCount()).To(BeNumerically(">", 0))
+			})
+		}
+	})
+
 })
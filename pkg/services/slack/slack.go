@@ -2,16 +2,19 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -27,19 +30,103 @@ const (
 
 // Send a notification message to Slack
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Slack, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	payload := CreateJSONPayload(config, message)
+	return service.sendPayload(ctx, &config, CreateJSONPayload(&config, message))
+}
 
-	var err error
-	if config.Token.IsAPIToken() {
-		err = service.sendAPI(config, payload)
-	} else {
-		err = service.sendWebhook(config, payload)
+// SendMarkdownContext converts the Markdown message to the slack mrkdwn format and sends it to Slack, aborting the
+// request if ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	return service.sendPayload(ctx, &config, CreateMarkdownPayload(&config, markdown.ToSlack(message)))
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	return service.buildRequests(ctx, &config, CreateJSONPayload(&config, message))
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, err
+	}
+
+	return service.buildRequests(ctx, &config, CreateMarkdownPayload(&config, markdown.ToSlack(message)))
+}
+
+func (service *Service) buildRequests(ctx context.Context, config *Config, payload interface{}) ([]*http.Request, error) {
+	req, err := service.buildRequest(ctx, config, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build slack request: %w", err)
+	}
+	return []*http.Request{req}, nil
+}
+
+// SendItems sends the message items to Slack as attachments
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the message items to Slack as attachments, with their level, timestamp and fields, aborting
+// the request if ctx is done. Any attachment files of the items are uploaded to the channel after the message, which
+// requires an API token.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
+		return err
+	}
+
+	files := types.ItemsAttachments(items)
+	if len(files) > 0 && !config.Token.IsAPIToken() {
+		return fmt.Errorf("failed to send slack notification: %w using webhook tokens", types.ErrAttachmentsUnsupported)
+	}
+
+	if err := service.sendPayload(ctx, &config, CreateItemsPayload(&config, items)); err != nil {
+		return err
+	}
+
+	if len(files) > 0 {
+		if err := uploadFiles(ctx, service.GetHTTPClient(), &config, files); err != nil {
+			return fmt.Errorf("failed to send slack notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (service *Service) sendPayload(ctx context.Context, config *Config, payload interface{}) error {
+	req, err := service.buildRequest(ctx, config, payload)
+	if err == nil {
+		if config.Token.IsAPIToken() {
+			err = service.sendAPI(ctx, config, req)
+		} else {
+			err = service.sendWebhook(req)
+		}
 	}
 
 	if err != nil {
@@ -59,35 +146,63 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 }
 
-func (service *Service) sendAPI(config *Config, payload interface{}) error {
-	response := APIResponse{}
-	jsonClient := jsonclient.NewClient()
+// buildRequest returns the request for posting the payload using the API or the webhook, depending on the token
+func (service *Service) buildRequest(ctx context.Context, config *Config, payload interface{}) (*http.Request, error) {
+	if config.Token.IsAPIToken() {
+		return service.apiClient(config).NewRequest(ctx, http.MethodPost, apiPostMessage, payload)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Token.WebhookURL(), bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", jsonclient.ContentType)
+
+	return req, nil
+}
+
+// apiClient returns a JSON client that authorizes its requests using the API token
+func (service *Service) apiClient(config *Config) jsonclient.Client {
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
 	jsonClient.Headers().Set("Authorization", config.Token.Authorization())
+	return jsonClient
+}
 
-	if err := jsonClient.Post(apiPostMessage, payload, &response); err != nil {
+func (service *Service) sendAPI(ctx context.Context, config *Config, req *http.Request) error {
+	response := APIResponse{}
+	if err := service.apiClient(config).Do(req, &response); err != nil {
 		return err
 	}
 
-	if !response.Ok {
-		if response.Error != "" {
-			return fmt.Errorf("api response: %v", response.Error)
-		}
-		return fmt.Errorf("unknown error")
+	if err := apiResponseError(response); err != nil {
+		return err
 	}
 
 	if response.Warning != "" {
 		service.Logger.Logf("Slack API warning: %q", response.Warning)
 	}
 
+	types.ReportMessageID(ctx, response.Timestamp)
 	return nil
 }
 
-func (service *Service) sendWebhook(config *Config, payload interface{}) error {
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+// apiResponseError returns the error reported in an API response, if it was not successful
+func apiResponseError(response APIResponse) error {
+	if !response.Ok {
+		if response.Error != "" {
+			return fmt.Errorf("api response: %v", response.Error)
+		}
+		return fmt.Errorf("unknown error")
 	}
-	res, err := http.Post(config.Token.WebhookURL(), jsonclient.ContentType, bytes.NewBuffer(payloadBytes))
+	return nil
+}
+
+func (service *Service) sendWebhook(req *http.Request) error {
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to invoke webhook: %w", err)
 	}
@@ -98,14 +213,14 @@ func (service *Service) sendWebhook(config *Config, payload interface{}) error {
 	switch response {
 	case "":
 		if res.StatusCode != http.StatusOK {
-			return fmt.Errorf("webhook status: %v", res.Status)
+			return retry.NewResponseError(res, fmt.Errorf("webhook status: %v", res.Status))
 		}
 		// Treat status 200 as no error regardless of actual content
 		fallthrough
 	case "ok":
 		return nil
 	default:
-		return fmt.Errorf("webhook response: %v", response)
+		return retry.NewResponseError(res, fmt.Errorf("webhook response: %v", response))
 	}
 
 }
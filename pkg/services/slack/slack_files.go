@@ -0,0 +1,103 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+)
+
+const (
+	apiGetUploadURL   = "https://slack.com/api/files.getUploadURLExternal"
+	apiCompleteUpload = "https://slack.com/api/files.completeUploadExternal"
+)
+
+// uploadURLResponse is the response of the files.getUploadURLExternal API
+type uploadURLResponse struct {
+	APIResponse
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+// completeUploadRequest is the payload of the files.completeUploadExternal API
+type completeUploadRequest struct {
+	Files     []uploadedFile `json:"files"`
+	ChannelID string         `json:"channel_id"`
+	ThreadTS  string         `json:"thread_ts,omitempty"`
+}
+
+type uploadedFile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// uploadFiles uploads the files and shares them in the configured channel, using the external upload API flow. It
+// requires the config to use an API token.
+func uploadFiles(ctx context.Context, httpClient *http.Client, config *Config, files []types.Attachment) error {
+	client := jsonclient.NewWithHTTPClient(httpClient)
+	client.Headers().Set("Authorization", config.Token.Authorization())
+
+	complete := completeUploadRequest{
+		ChannelID: config.Channel,
+		ThreadTS:  config.ThreadTS,
+	}
+
+	for _, file := range files {
+		fileID, err := uploadFile(ctx, httpClient, client, file)
+		if err != nil {
+			return fmt.Errorf("failed to upload %q: %w", file.Name, err)
+		}
+		complete.Files = append(complete.Files, uploadedFile{ID: fileID, Title: file.Name})
+	}
+
+	response := APIResponse{}
+	if err := client.PostContext(ctx, apiCompleteUpload, complete, &response); err != nil {
+		return err
+	}
+	return apiResponseError(response)
+}
+
+// uploadFile sends the content of the file to a new upload URL, returning the ID of the file
+func uploadFile(ctx context.Context, httpClient *http.Client, client jsonclient.Client, file types.Attachment) (string, error) {
+	form := url.Values{
+		"filename": []string{file.Name},
+		"length":   []string{strconv.Itoa(len(file.Content))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiGetUploadURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response := uploadURLResponse{}
+	if err := client.Do(req, &response); err != nil {
+		return "", err
+	}
+	if err := apiResponseError(response.APIResponse); err != nil {
+		return "", err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, response.UploadURL, bytes.NewReader(file.Content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", file.MediaType())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", retry.NewResponseError(res, fmt.Errorf("upload status: %v", res.Status))
+	}
+
+	return response.FileID, nil
+}
@@ -3,6 +3,8 @@ package slack
 import (
 	"regexp"
 	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
 )
 
 // MessagePayload used within the Slack service
@@ -19,6 +21,9 @@ type MessagePayload struct {
 
 var iconURLPattern = regexp.MustCompile(`https?://`)
 
+// maxAttachments is the maximum number of attachments allowed by the slack API
+const maxAttachments = 100
+
 // SetIcon sets the appropriate icon field in the payload based on whether the input is a URL or not
 func (p *MessagePayload) SetIcon(icon string) {
 	p.IconURL = ""
@@ -61,10 +66,11 @@ type legacyField struct {
 
 // APIResponse is the default generic response message sent from the API
 type APIResponse struct {
-	Ok       bool   `json:"ok"`
-	Error    string `json:"error"`
-	Warning  string `json:"warning"`
-	MetaData struct {
+	Ok        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Timestamp string `json:"ts"`
+	Warning   string `json:"warning"`
+	MetaData  struct {
 		Warnings []string `json:"warnings"`
 	} `json:"response_metadata"`
 }
@@ -76,7 +82,7 @@ func CreateJSONPayload(config *Config, message string) interface{} {
 	for i, line := range strings.Split(message, "\n") {
 		// When 100 attachments have been reached, append the remaining line to the last
 		// attachment to prevent reaching the slack API limit
-		if i >= 100 {
+		if i >= maxAttachments {
 			atts[len(atts)-1].Text += "\n" + line
 			continue
 		}
@@ -91,6 +97,65 @@ func CreateJSONPayload(config *Config, message string) interface{} {
 		atts = atts[:len(atts)-1]
 	}
 
+	return createPayload(config, atts)
+}
+
+// CreateMarkdownPayload creates a payload compatible with the slack post message API, with the message in the slack
+// mrkdwn format as a single attachment, since splitting it by line would break up formatted blocks
+func CreateMarkdownPayload(config *Config, mrkdwn string) interface{} {
+	return createPayload(config, []attachment{{
+		Text:  mrkdwn,
+		Color: config.Color,
+	}})
+}
+
+// CreateItemsPayload creates a payload compatible with the slack post message API, with one attachment for each
+// message item, using the level for the color and footer
+func CreateItemsPayload(config *Config, items []types.MessageItem) interface{} {
+	atts := make([]attachment, 0, len(items))
+	for i, item := range items {
+		// Only the text of the items beyond the slack API limit is kept, appended to the last attachment
+		if i >= maxAttachments {
+			atts[len(atts)-1].Text += "\n" + item.Text
+			continue
+		}
+
+		att := attachment{
+			Text:  item.Text,
+			Color: levelColor(item.Level, config.Color),
+		}
+		if item.Level != types.Unknown {
+			att.Footer = item.Level.String()
+		}
+		if !item.Timestamp.IsZero() {
+			att.Time = int(item.Timestamp.Unix())
+		}
+		for _, field := range item.Fields {
+			att.Fields = append(att.Fields, legacyField{
+				Title: field.Key,
+				Value: field.Value,
+				Short: true,
+			})
+		}
+		atts = append(atts, att)
+	}
+
+	return createPayload(config, atts)
+}
+
+// levelColor returns the slack attachment color for the level, using the configured color for levels that are not
+// errors or warnings
+func levelColor(level types.MessageLevel, defaultColor string) string {
+	switch level {
+	case types.Error:
+		return "danger"
+	case types.Warning:
+		return "warning"
+	}
+	return defaultColor
+}
+
+func createPayload(config *Config, atts []attachment) MessagePayload {
 	payload := MessagePayload{
 		ThreadTS:    config.ThreadTS,
 		Text:        config.Title,
@@ -1,19 +1,25 @@
 package smtp
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"math/rand"
+	"mime"
 	"net"
 	"net/smtp"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
 )
 
 // Service sends notifications to a given e-mail addresses via SMTP
@@ -22,6 +28,7 @@ type Service struct {
 	standard.Templater
 	config            *Config
 	multipartBoundary string
+	mixedBoundary     string
 	propKeyResolver   format.PropKeyResolver
 }
 
@@ -29,6 +36,10 @@ const (
 	contentHTML      = "text/html; charset=\"UTF-8\""
 	contentPlain     = "text/plain; charset=\"UTF-8\""
 	contentMultipart = "multipart/alternative; boundary=%s"
+	contentMixed     = "multipart/mixed; boundary=%s"
+
+	// base64LineLength is the maximum length of the lines of base64 encoded content, as specified by RFC 2045
+	base64LineLength = 76
 )
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -66,32 +77,93 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to e-mail recipients
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to e-mail recipients, closing the connection if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := service.config.Clone()
+	if err := service.propKeyResolver.UpdateConfigFromParams(&config, params); err != nil {
+		return fail(FailApplySendParams, err)
+	}
+
+	return service.send(ctx, newMessageBody(message, &config), &config)
+}
+
+// SendMarkdownContext sends the Markdown message to e-mail recipients as a multipart message, with the message
+// converted to HTML and plain text, closing the connection if ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
 	config := service.config.Clone()
 	if err := service.propKeyResolver.UpdateConfigFromParams(&config, params); err != nil {
 		return fail(FailApplySendParams, err)
 	}
 
-	client, err := getClientConnection(service.config)
+	body := messageBody{
+		plain: markdown.ToPlain(message),
+		html:  markdown.ToHTML(message),
+	}
+	return service.send(ctx, body, &config)
+}
+
+// SendItems sends the message items to e-mail recipients as a multipart message, with a HTML part that includes their
+// level, timestamp and fields, and any attachments of the items as MIME attachments
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the message items to e-mail recipients, closing the connection if ctx is done
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	config := service.config.Clone()
+	if err := service.propKeyResolver.UpdateConfigFromParams(&config, &params); err != nil {
+		return fail(FailApplySendParams, err)
+	}
+
+	body := messageBody{
+		plain:       strings.TrimSuffix(types.ItemsToPlain(items), "\n"),
+		html:        createItemsHTML(items),
+		attachments: types.ItemsAttachments(items),
+	}
+	return service.send(ctx, body, &config)
+}
+
+// send connects to the server and sends the message body to the recipients, closing the connection if ctx is done
+func (service *Service) send(ctx context.Context, body messageBody, config *Config) error {
+	client, err := getClientConnection(ctx, config)
 	if err != nil {
 		return fail(FailGetSMTPClient, err)
 	}
 
-	return service.doSend(client, message, &config)
+	// Abort the SMTP session if the context is done before it has been completed
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = client.Close()
+		case <-done:
+		}
+	}()
+
+	return service.doSend(client, body, config)
 }
 
-func getClientConnection(config *Config) (*smtp.Client, error) {
+func getClientConnection(ctx context.Context, config *Config) (*smtp.Client, error) {
 
 	var conn net.Conn
 	var err error
 
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(int(config.Port)))
 
 	if useImplicitTLS(config.Encryption, config.Port) {
-		conn, err = tls.Dial("tcp", addr, &tls.Config{
-			ServerName: config.Host,
-		})
+		dialer := &tls.Dialer{
+			Config: &tls.Config{
+				ServerName: config.Host,
+			},
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
 	} else {
-		conn, err = net.Dial("tcp", addr)
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
 	}
 
 	if err != nil {
@@ -106,7 +178,7 @@ func getClientConnection(config *Config) (*smtp.Client, error) {
 	return client, nil
 }
 
-func (service *Service) doSend(client *smtp.Client, message string, config *Config) failure {
+func (service *Service) doSend(client *smtp.Client, body messageBody, config *Config) failure {
 
 	config.FixEmailTags()
 
@@ -116,9 +188,12 @@ func (service *Service) doSend(client *smtp.Client, message string, config *Conf
 		return fail(FailHandshake, err)
 	}
 
-	if config.UseHTML {
+	if body.isMultipart() {
 		service.multipartBoundary = fmt.Sprintf("%x", rand.Int63())
 	}
+	if len(body.attachments) > 0 {
+		service.mixedBoundary = fmt.Sprintf("%x", rand.Int63())
+	}
 
 	if config.UseStartTLS && !useImplicitTLS(config.Encryption, config.Port) {
 		if supported, _ := client.Extension("StartTLS"); !supported {
@@ -142,7 +217,7 @@ func (service *Service) doSend(client *smtp.Client, message string, config *Conf
 
 	for _, toAddress := range config.ToAddresses {
 
-		err := service.sendToRecipient(client, toAddress, config, message)
+		err := service.sendToRecipient(client, toAddress, config, body)
 		if err != nil {
 			return fail(FailSendRecipient, err)
 		}
@@ -190,7 +265,7 @@ func (service *Service) getAuth(config *Config) (smtp.Auth, failure) {
 
 }
 
-func (service *Service) sendToRecipient(client *smtp.Client, toAddress string, config *Config, message string) failure {
+func (service *Service) sendToRecipient(client *smtp.Client, toAddress string, config *Config, body messageBody) failure {
 
 	// Set the sender and recipient first
 	if err := client.Mail(config.FromAddress); err != nil {
@@ -206,15 +281,15 @@ func (service *Service) sendToRecipient(client *smtp.Client, toAddress string, c
 		return fail(FailOpenDataStream, err)
 	}
 
-	if err := writeHeaders(wc, service.getHeaders(toAddress, config.Subject)); err != nil {
+	if err := writeHeaders(wc, service.getHeaders(toAddress, config, body)); err != nil {
 		return err
 	}
 
 	var ferr failure
-	if config.UseHTML {
-		ferr = service.writeMultipartMessage(wc, message)
+	if len(body.attachments) > 0 {
+		ferr = service.writeMixedMessage(wc, body)
 	} else {
-		ferr = service.writeMessagePart(wc, message, "plain")
+		ferr = service.writeMessageBody(wc, body)
 	}
 
 	if ferr != nil {
@@ -228,18 +303,16 @@ func (service *Service) sendToRecipient(client *smtp.Client, toAddress string, c
 	return nil
 }
 
-func (service *Service) getHeaders(toAddress string, subject string) map[string]string {
-	conf := service.config
-
+func (service *Service) getHeaders(toAddress string, conf *Config, body messageBody) map[string]string {
 	var contentType string
-	if conf.UseHTML {
-		contentType = fmt.Sprintf(contentMultipart, service.multipartBoundary)
+	if len(body.attachments) > 0 {
+		contentType = fmt.Sprintf(contentMixed, service.mixedBoundary)
 	} else {
-		contentType = contentPlain
+		contentType = service.bodyContentType(body)
 	}
 
 	return map[string]string{
-		"Subject":      subject,
+		"Subject":      conf.Subject,
 		"Date":         time.Now().Format(time.RFC1123Z),
 		"To":           toAddress,
 		"From":         fmt.Sprintf("%s <%s>", conf.FromName, conf.FromAddress),
@@ -248,19 +321,57 @@ func (service *Service) getHeaders(toAddress string, subject string) map[string]
 	}
 }
 
-func (service *Service) writeMultipartMessage(wc io.WriteCloser, message string) failure {
+// bodyContentType returns the content type of the message body, excluding the attachments
+func (service *Service) bodyContentType(body messageBody) string {
+	if body.isMultipart() {
+		return fmt.Sprintf(contentMultipart, service.multipartBoundary)
+	}
+	return contentPlain
+}
+
+// writeMessageBody writes the message body, as a multipart message if it has a HTML part, excluding the attachments
+func (service *Service) writeMessageBody(wc io.WriteCloser, body messageBody) failure {
+	if body.isMultipart() {
+		return service.writeMultipartMessage(wc, body)
+	}
+	return service.writeMessagePart(wc, body.plain, "plain")
+}
+
+// writeMixedMessage writes the message body followed by the attachments, as a multipart/mixed message
+func (service *Service) writeMixedMessage(wc io.WriteCloser, body messageBody) failure {
+	if err := writeMultipartHeader(wc, service.mixedBoundary, service.bodyContentType(body)); err != nil {
+		return fail(FailPlainHeader, err)
+	}
+	if err := service.writeMessageBody(wc, body); err != nil {
+		return err
+	}
+
+	for _, attachment := range body.attachments {
+		if err := writeAttachmentPart(wc, service.mixedBoundary, attachment); err != nil {
+			return fail(FailAttachment, err, attachment.Name)
+		}
+	}
+
+	if err := writeMultipartHeader(wc, service.mixedBoundary, ""); err != nil {
+		return fail(FailMultiEndHeader, err)
+	}
+
+	return nil
+}
+
+func (service *Service) writeMultipartMessage(wc io.WriteCloser, body messageBody) failure {
 
 	if err := writeMultipartHeader(wc, service.multipartBoundary, contentPlain); err != nil {
 		return fail(FailPlainHeader, err)
 	}
-	if err := service.writeMessagePart(wc, message, "plain"); err != nil {
+	if err := service.writeMessagePart(wc, body.plain, "plain"); err != nil {
 		return err
 	}
 
 	if err := writeMultipartHeader(wc, service.multipartBoundary, contentHTML); err != nil {
 		return fail(FailHTMLHeader, err)
 	}
-	if err := service.writeMessagePart(wc, message, "HTML"); err != nil {
+	if err := service.writeMessagePart(wc, body.html, "HTML"); err != nil {
 		return err
 	}
 
@@ -306,6 +417,34 @@ func writeMultipartHeader(wc io.WriteCloser, boundary string, contentType string
 	return nil
 }
 
+// writeAttachmentPart writes the attachment as a base64 encoded part of a multipart message
+func writeAttachmentPart(wc io.WriteCloser, boundary string, attachment types.Attachment) error {
+	name := mime.QEncoding.Encode("utf-8", attachment.Name)
+	if _, err := fmt.Fprintf(wc, "\n\n--%s\nContent-Type: %s; name=\"%s\"\n", boundary, attachment.MediaType(), name); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(wc, "Content-Disposition: attachment; filename=\"%s\"\n", name); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(wc, "Content-Transfer-Encoding: base64\n\n"); err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 0 {
+		line := encoded
+		if len(line) > base64LineLength {
+			line = line[:base64LineLength]
+		}
+		if _, err := fmt.Fprintf(wc, "%s\n", line); err != nil {
+			return err
+		}
+		encoded = encoded[len(line):]
+	}
+
+	return nil
+}
+
 func writeHeaders(wc io.WriteCloser, headers map[string]string) failure {
 	for key, val := range headers {
 		if _, err := fmt.Fprintf(wc, "%s: %s\n", key, val); err != nil {
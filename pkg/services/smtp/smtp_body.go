@@ -0,0 +1,75 @@
+package smtp
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// messageBody contains the rendered content of an e-mail
+type messageBody struct {
+	plain string
+	// html is sent as an alternative to the plain part, if set
+	html        string
+	attachments []types.Attachment
+}
+
+// newMessageBody returns the body for a plain message, using it as the HTML part as well if the config enables HTML
+func newMessageBody(message string, config *Config) messageBody {
+	body := messageBody{plain: message}
+	if config.UseHTML {
+		body.html = message
+	}
+	return body
+}
+
+func (body messageBody) isMultipart() bool {
+	return body.html != ""
+}
+
+// levelColors are the HTML colors used for indicating the message item levels
+var levelColors = [types.MessageLevelCount]string{
+	types.Debug:   "#808080",
+	types.Info:    "#2f6fbd",
+	types.Warning: "#c98a00",
+	types.Error:   "#c80000",
+}
+
+// createItemsHTML renders the message items as a HTML fragment
+func createItemsHTML(items []types.MessageItem) string {
+	sb := strings.Builder{}
+
+	for _, item := range items {
+		sb.WriteString("<div>")
+
+		var header []string
+		if item.Level != types.Unknown && item.Level < types.MessageLevel(types.MessageLevelCount) {
+			header = append(header, fmt.Sprintf(`<strong style="color: %s">%s</strong>`,
+				levelColors[item.Level], item.Level.String()))
+		}
+		if !item.Timestamp.IsZero() {
+			header = append(header, "<em>"+item.Timestamp.UTC().Format(time.RFC3339)+"</em>")
+		}
+		if len(header) > 0 {
+			sb.WriteString("<p>" + strings.Join(header, " ") + "</p>")
+		}
+
+		sb.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(item.Text), "\n", "<br>") + "</p>")
+
+		if len(item.Fields) > 0 {
+			sb.WriteString("<table>")
+			for _, field := range item.Fields {
+				sb.WriteString(fmt.Sprintf("<tr><th align=\"left\">%s</th><td>%s</td></tr>",
+					html.EscapeString(field.Key), html.EscapeString(field.Value)))
+			}
+			sb.WriteString("</table>")
+		}
+
+		sb.WriteString("</div>\n")
+	}
+
+	return sb.String()
+}
@@ -45,6 +45,8 @@ const (
 	FailApplySendParams
 	// FailHandshake is returned when the initial HELLO handshake returned an error
 	FailHandshake
+	// FailAttachment is returned when an attachment could not be written to the stream
+	FailAttachment
 )
 
 func fail(failureID failures.FailureID, err error, v ...interface{}) failure {
@@ -90,6 +92,8 @@ func fail(failureID failures.FailureID, err error, v ...interface{}) failure {
 		msg = "error applying params to send config"
 	case FailHandshake:
 		msg = "server did not accept the handshake"
+	case FailAttachment:
+		msg = "error writing attachment '%s'"
 	// case FailUnknown:
 	default:
 		msg = "an unknown error occurred"
@@ -1,12 +1,18 @@
 package smtp
 
 import (
+	"bytes"
+	"encoding/base64"
+	"io"
 	"log"
+	"mime/multipart"
 	"net/smtp"
 	"net/url"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/containrrr/shoutrrr/internal/failures"
@@ -142,35 +148,35 @@ var _ = Describe("the SMTP service", func() {
 		})
 		It("should fail when writing multipart plain header", func() {
 			writer := testutils.CreateFailWriter(1)
-			err := service.writeMultipartMessage(writer, message)
+			err := service.writeMultipartMessage(writer, messageBody{plain: message, html: message})
 			Expect(err).To(HaveOccurred())
 			Expect(err.ID()).To(Equal(FailPlainHeader))
 		})
 
 		It("should fail when writing multipart plain message", func() {
 			writer := testutils.CreateFailWriter(2)
-			err := service.writeMultipartMessage(writer, message)
+			err := service.writeMultipartMessage(writer, messageBody{plain: message, html: message})
 			Expect(err).To(HaveOccurred())
 			Expect(err.ID()).To(Equal(FailMessageRaw))
 		})
 
 		It("should fail when writing multipart HTML header", func() {
 			writer := testutils.CreateFailWriter(4)
-			err := service.writeMultipartMessage(writer, message)
+			err := service.writeMultipartMessage(writer, messageBody{plain: message, html: message})
 			Expect(err).To(HaveOccurred())
 			Expect(err.ID()).To(Equal(FailHTMLHeader))
 		})
 
 		It("should fail when writing multipart HTML message", func() {
 			writer := testutils.CreateFailWriter(5)
-			err := service.writeMultipartMessage(writer, message)
+			err := service.writeMultipartMessage(writer, messageBody{plain: message, html: message})
 			Expect(err).To(HaveOccurred())
 			Expect(err.ID()).To(Equal(FailMessageRaw))
 		})
 
 		It("should fail when writing multipart end header", func() {
 			writer := testutils.CreateFailWriter(6)
-			err := service.writeMultipartMessage(writer, message)
+			err := service.writeMultipartMessage(writer, messageBody{plain: message, html: message})
 			Expect(err).To(HaveOccurred())
 			Expect(err.ID()).To(Equal(FailMultiEndHeader))
 		})
@@ -458,6 +464,53 @@ var _ = Describe("the SMTP service", func() {
 
 		})
 	})
+	When("sending message items", func() {
+		items := []types.MessageItem{
+			{
+				Text:      "Backup <failed>",
+				Level:     types.Error,
+				Timestamp: time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+				Fields:    []types.Field{{Key: "job", Value: "nightly & weekly"}},
+			},
+		}
+		It("should render the level, timestamp and fields as HTML", func() {
+			rendered := createItemsHTML(items)
+			Expect(rendered).To(ContainSubstring(">Error</strong>"))
+			Expect(rendered).To(ContainSubstring("<em>2022-03-04T05:06:07Z</em>"))
+			Expect(rendered).To(ContainSubstring("<p>Backup &lt;failed&gt;</p>"))
+			Expect(rendered).To(ContainSubstring("<th align=\"left\">job</th><td>nightly &amp; weekly</td>"))
+		})
+		It("should add the attachments as base64 encoded parts", func() {
+			service := Service{multipartBoundary: "alt", mixedBoundary: "mixed"}
+			body := messageBody{
+				plain:       "text",
+				html:        "html",
+				attachments: []types.Attachment{types.NewAttachment("server.log", []byte("log content"))},
+			}
+			writer := &bufferWriteCloser{}
+			Expect(service.writeMixedMessage(writer, body)).To(Succeed())
+
+			reader := multipart.NewReader(strings.NewReader(writer.String()), "mixed")
+			part, err := reader.NextPart()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(part.Header.Get("Content-Type")).To(Equal("multipart/alternative; boundary=alt"))
+
+			part, err = reader.NextPart()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(part.FileName()).To(Equal("server.log"))
+			content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("log content"))
+
+			_, err = reader.NextPart()
+			Expect(err).To(Equal(io.EOF))
+		})
+		It("should use a multipart message even if HTML is disabled", func() {
+			service := Service{config: &Config{}}
+			headers := service.getHeaders("r@example.com", service.config, messageBody{plain: "text", html: "html"})
+			Expect(headers["Content-Type"]).To(HavePrefix("multipart/alternative"))
+		})
+	})
 	When("writing headers and the output stream is closed", func() {
 		When("it's closed during header content", func() {
 			It("should fail with correct error", func() {
@@ -500,7 +553,7 @@ func testSendRecipient(testURL string, responses []string) failures.Failure {
 	config := &Config{}
 	message := "message body"
 
-	ferr := service.sendToRecipient(client, "r@example.com", config, message)
+	ferr := service.sendToRecipient(client, "r@example.com", config, messageBody{plain: message})
 
 	logger.Printf("\n%s", tcfaker.GetConversation(false))
 	if ferr != nil {
@@ -541,7 +594,7 @@ func testIntegration(testURL string, responses []string, htmlTemplate string, pl
 
 	fakeTLSEnabled(client, serviceURL.Hostname())
 
-	ferr := service.doSend(client, "Test message", service.config)
+	ferr := service.doSend(client, newMessageBody("Test message", service.config), service.config)
 
 	recieved := tcfaker.GetClientSentences()
 	for _, expected := range expectRec {
@@ -556,6 +609,15 @@ func testIntegration(testURL string, responses []string, htmlTemplate string, pl
 	return nil
 }
 
+// bufferWriteCloser is a bytes.Buffer that implements io.WriteCloser
+type bufferWriteCloser struct {
+	bytes.Buffer
+}
+
+func (bwc *bufferWriteCloser) Close() error {
+	return nil
+}
+
 // fakeTLSEnabled tricks a given client into believing that TLS is enabled even though it's not
 // this is needed because the SMTP library won't allow plain authentication without TLS being turned on.
 // having it turned on would of course mean that we cannot test the communication since it will be encrypted.
@@ -1,7 +1,8 @@
 package standard
 
-// Standard implements the Logger and Templater parts of the Service interface
+// Standard implements the Logger, Templater and HTTP client parts of the Service interface
 type Standard struct {
 	Logger
 	Templater
+	HTTPClient
 }
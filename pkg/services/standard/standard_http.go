@@ -0,0 +1,22 @@
+package standard
+
+import "net/http"
+
+// HTTPClient provides the client used by the service for its HTTP requests
+type HTTPClient struct {
+	httpClient *http.Client
+}
+
+// SetHTTPClient sets the client used by the service for its HTTP requests. A nil client resets it to http.DefaultClient.
+func (hc *HTTPClient) SetHTTPClient(client *http.Client) {
+	hc.httpClient = client
+}
+
+// GetHTTPClient returns the client used by the service for its HTTP requests, which is http.DefaultClient unless
+// another client has been set
+func (hc *HTTPClient) GetHTTPClient() *http.Client {
+	if hc.httpClient == nil {
+		return http.DefaultClient
+	}
+	return hc.httpClient
+}
@@ -3,6 +3,8 @@ package standard
 import (
 	"io/ioutil"
 	"text/template"
+
+	"github.com/containrrr/shoutrrr/pkg/util/templates"
 )
 
 // Templater is the standard implementation of ApplyTemplate using the "text/template" library
@@ -16,9 +18,10 @@ func (templater *Templater) GetTemplate(id string) (template *template.Template,
 	return tpl, found
 }
 
-// SetTemplateString creates a new template from the body and assigning it the id
+// SetTemplateString creates a new template from the body and assigning it the id. The template helper functions are
+// available in the body.
 func (templater *Templater) SetTemplateString(id string, body string) error {
-	tpl, err := template.New("").Parse(body)
+	tpl, err := templates.Parse("", body)
 	if err != nil {
 		return err
 	}
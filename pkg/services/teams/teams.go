@@ -2,6 +2,7 @@ package teams
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service providing teams as a notification service
@@ -23,13 +25,50 @@ type Service struct {
 
 // Send a notification message to Microsoft Teams
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Microsoft Teams, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		service.Logf("Failed to update params: %v", err)
 	}
 
-	return service.doSend(config, message)
+	return service.doSend(ctx, &config, message)
+}
+
+// SendMarkdownContext sends the Markdown message to Microsoft Teams, which renders it natively, aborting the request if
+// ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.SendContext(ctx, message, params)
+}
+
+// SendItems sends the message items to Microsoft Teams as card sections
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the message items to Microsoft Teams as card sections, with their level, timestamp and
+// fields, aborting the request if ctx is done. Attachments are not supported by the webhook API.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	if len(types.ItemsAttachments(items)) > 0 {
+		return fmt.Errorf("failed to send notification to teams: %w", types.ErrAttachmentsUnsupported)
+	}
+
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
+		service.Logf("Failed to update params: %v", err)
+	}
+
+	sections, themeColor := createItemSections(items)
+	if themeColor == "" {
+		themeColor = config.Color
+	}
+
+	return service.sendSections(ctx, &config, sections, themeColor)
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -61,7 +100,32 @@ func (*Service) GetConfigURLFromCustom(customURL *url.URL) (serviceURL *url.URL,
 	return config.getURL(&resolver), nil
 }
 
-func (service *Service) doSend(config *Config, message string) error {
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		service.Logf("Failed to update params: %v", err)
+	}
+
+	req, err := service.buildRequest(ctx, &config, messageSections(message), config.Color)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.BuildRequests(ctx, message, params)
+}
+
+func (service *Service) doSend(ctx context.Context, config *Config, message string) error {
+	return service.sendSections(ctx, config, messageSections(message), config.Color)
+}
+
+// messageSections returns a card section for every line of the message
+func messageSections(message string) []section {
 	var sections []section
 
 	for _, line := range strings.Split(message, "\n") {
@@ -70,6 +134,33 @@ func (service *Service) doSend(config *Config, message string) error {
 		})
 	}
 
+	return sections
+}
+
+func (service *Service) sendSections(ctx context.Context, config *Config, sections []section, themeColor string) error {
+	req, err := service.buildRequest(ctx, config, sections, themeColor)
+	if err != nil {
+		return err
+	}
+
+	res, err := service.GetHTTPClient().Do(req)
+	if err == nil {
+		defer res.Body.Close()
+	}
+	if err == nil && res.StatusCode != http.StatusOK {
+		return retry.NewResponseError(res, fmt.Errorf("failed to send notification to teams, response status code %s", res.Status))
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"an error occurred while sending notification to teams: %w",
+			err,
+		)
+	}
+	return nil
+}
+
+// buildRequest returns the webhook request for posting a card with the sections
+func (service *Service) buildRequest(ctx context.Context, config *Config, sections []section, themeColor string) (*http.Request, error) {
 	// Teams need a summary for the webhook, use title or first (truncated) row
 	summary := config.Title
 	if summary == "" && len(sections) > 0 {
@@ -84,12 +175,12 @@ func (service *Service) doSend(config *Config, message string) error {
 		Context:    "http://schema.org/extensions",
 		Markdown:   true,
 		Title:      config.Title,
-		ThemeColor: config.Color,
+		ThemeColor: themeColor,
 		Summary:    summary,
 		Sections:   sections,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	host := config.Host
@@ -101,15 +192,11 @@ func (service *Service) doSend(config *Config, message string) error {
 	}
 	postURL := buildWebhookURL(host, config.Group, config.Tenant, config.AltID, config.GroupOwner)
 
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
-	if err == nil && res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to teams, response status code %s", res.Status)
-	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf(
-			"an error occurred while sending notification to teams: %s",
-			err.Error(),
-		)
+		return nil, err
 	}
-	return nil
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
 }
@@ -16,8 +16,8 @@ type Config struct {
 	standard.EnumlessConfig
 	Group      string `url:"user" optional:""`
 	Tenant     string `url:"host" optional:""`
-	AltID      string `url:"path1" optional:""`
-	GroupOwner string `url:"path2" optional:""`
+	AltID      string `url:"path1" optional:"" secret:""`
+	GroupOwner string `url:"path2" optional:"" secret:""`
 
 	Title string `key:"title" optional:""`
 	Color string `key:"color" optional:""`
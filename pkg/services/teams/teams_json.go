@@ -1,5 +1,11 @@
 package teams
 
+import (
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
 // JSON is the actual payload being sent to the teams api
 type payload struct {
 	CardType   string    `json:"@type"`
@@ -13,13 +19,49 @@ type payload struct {
 }
 
 type section struct {
-	Text         string `json:"text,omitempty"`
-	ActivityText string `json:"activityText,omitempty"`
-	StartGroup   bool   `json:"startGroup"`
-	Facts        []fact `json:"facts,omitempty"`
+	Text             string `json:"text,omitempty"`
+	ActivityTitle    string `json:"activityTitle,omitempty"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+	ActivityText     string `json:"activityText,omitempty"`
+	StartGroup       bool   `json:"startGroup"`
+	Facts            []fact `json:"facts,omitempty"`
 }
 
 type fact struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// levelColors are the card theme colors used for the message item levels, where the empty string means that the
+// configured color should be used
+var levelColors = [types.MessageLevelCount]string{
+	types.Error:   "D70000",
+	types.Warning: "FFC107",
+}
+
+// createItemSections creates a card section for each of the message items, returning them together with the theme
+// color of the most severe item level
+func createItemSections(items []types.MessageItem) (sections []section, themeColor string) {
+	maxLevel := types.Unknown
+	for i, item := range items {
+		sec := section{
+			Text:       item.Text,
+			StartGroup: i > 0,
+		}
+		if item.Level != types.Unknown {
+			sec.ActivityTitle = item.Level.String()
+		}
+		if !item.Timestamp.IsZero() {
+			sec.ActivitySubtitle = item.Timestamp.UTC().Format(time.RFC3339)
+		}
+		for _, field := range item.Fields {
+			sec.Facts = append(sec.Facts, fact{Key: field.Key, Value: field.Value})
+		}
+		sections = append(sections, sec)
+
+		if item.Level > maxLevel && item.Level < types.MessageLevel(types.MessageLevelCount) {
+			maxLevel = item.Level
+		}
+	}
+	return sections, levelColors[maxLevel]
+}
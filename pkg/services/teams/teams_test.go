@@ -1,10 +1,16 @@
 package teams
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"log"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
 
 	"github.com/jarcoal/httpmock"
 
@@ -149,6 +155,55 @@ var _ = Describe("the teams service", func() {
 			err = service.Send("Message", nil)
 			Expect(err).To(HaveOccurred())
 		})
+		It("should send message items as card sections", func() {
+			serviceURL, _ := url.Parse(scopedURLBase)
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			var body payload
+			httpmock.RegisterResponder("POST", scopedWebhookURL, func(req *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return nil, err
+				}
+				return httpmock.NewStringResponse(200, ""), nil
+			})
+
+			item := types.MessageItem{Text: "Disk full", Level: types.Warning, Timestamp: time.Unix(1640995200, 0)}
+			item.WithField("Host", "db-1")
+			Expect(service.SendItems([]types.MessageItem{{Text: "Started"}, item}, nil)).To(Succeed())
+
+			Expect(body.ThemeColor).To(Equal("FFC107"))
+			Expect(body.Sections).To(Equal([]section{
+				{Text: "Started"},
+				{
+					Text:             "Disk full",
+					ActivityTitle:    "Warning",
+					ActivitySubtitle: "2022-01-01T00:00:00Z",
+					StartGroup:       true,
+					Facts:            []fact{{Key: "Host", Value: "db-1"}},
+				},
+			}))
+		})
+		It("should build the card request without sending it", func() {
+			serviceURL, _ := url.Parse(scopedURLBase + "&title=Alert")
+			Expect(service.Initialize(serviceURL, logger)).To(Succeed())
+
+			requests, err := service.BuildRequests(context.Background(), "first\nsecond", &types.Params{"color": "ff0000"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(HaveLen(1))
+			Expect(httpmock.GetTotalCallCount()).To(BeZero())
+			Expect(service.config.Color).To(BeEmpty())
+
+			req := requests[0]
+			Expect(req.Method).To(Equal(http.MethodPost))
+			Expect(req.URL.String()).To(Equal(scopedWebhookURL))
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			var body payload
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			Expect(body.Title).To(Equal("Alert"))
+			Expect(body.ThemeColor).To(Equal("ff0000"))
+			Expect(body.Sections).To(Equal([]section{{Text: "first"}, {Text: "second"}}))
+		})
 
 	})
 
@@ -1,12 +1,17 @@
 package telegram
 
 import (
+	"context"
 	"errors"
 	"github.com/containrrr/shoutrrr/pkg/format"
+	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/markdown"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 const (
@@ -23,16 +28,118 @@ type Service struct {
 
 // Send notification to Telegram
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification to Telegram, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	message, config, err := service.plainMessage(message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.sendMessageForChatIDs(ctx, message, config)
+}
+
+// SendMarkdownContext converts the Markdown message to the HTML supported by Telegram and sends it, aborting the
+// request if ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	message, config, err := service.markdownMessage(message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.sendMessageForChatIDs(ctx, message, config)
+}
+
+// BuildRequests returns the requests that SendContext would make for the message, without sending them
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	message, config, err := service.plainMessage(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.buildMessageRequests(ctx, message, config)
+}
+
+// BuildMarkdownRequests returns the requests that SendMarkdownContext would make for the message, without sending them
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	message, config, err := service.markdownMessage(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.buildMessageRequests(ctx, message, config)
+}
+
+// plainMessage returns the message and the config to send it with, with the params applied
+func (service *Service) plainMessage(message string, params *types.Params) (string, *Config, error) {
 	if len(message) > maxlength {
-		return errors.New("Message exceeds the max length")
+		return "", nil, errors.New("Message exceeds the max length")
+	}
+
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return "", nil, err
 	}
 
+	return message, &config, nil
+}
+
+// markdownMessage returns the Markdown message converted to HTML and the config to send it with, with the params
+// applied
+func (service *Service) markdownMessage(message string, params *types.Params) (string, *Config, error) {
 	config := *service.config
 	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return "", nil, err
+	}
+
+	message = markdown.ToLimitedHTML(message, markdown.TelegramTags)
+	if len(message) > maxlength {
+		return "", nil, errors.New("Message exceeds the max length")
+	}
+
+	// The converted message is HTML, regardless of the configured parse mode
+	config.ParseMode = ParseModes.HTML
+	return message, &config, nil
+}
+
+// MessageLimit returns the maximum length of messages sent to Telegram
+func (service *Service) MessageLimit() types.MessageLimit {
+	return types.MessageLimit{ChunkSize: maxlength, TotalChunkSize: maxlength, ChunkCount: 1}
+}
+
+// SendItems sends the message items to Telegram as a HTML formatted message
+func (service *Service) SendItems(items []types.MessageItem, params types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the message items to Telegram as a HTML formatted message, with their level, timestamp and
+// fields, aborting the request if ctx is done. Any attachments of the items are sent after the message, as photos or
+// documents.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
 		return err
 	}
 
-	return service.sendMessageForChatIDs(message, &config)
+	message := createItemsHTML(items)
+	if len(message) > maxlength {
+		return errors.New("Message exceeds the max length")
+	}
+
+	attachments := types.ItemsAttachments(items)
+	if message == "" && len(attachments) > 0 {
+		return service.sendFilesForChatIDs(ctx, attachments, &config)
+	}
+
+	// The items are rendered as HTML, regardless of the configured parse mode
+	config.ParseMode = ParseModes.HTML
+	if err := service.sendMessageForChatIDs(ctx, message, &config); err != nil {
+		return err
+	}
+	// The message has already been sent, so retrying would send it again
+	return retry.Partial(service.sendFilesForChatIDs(ctx, attachments, &config))
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -50,23 +157,72 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	return nil
 }
 
-func (service *Service) sendMessageForChatIDs(message string, config *Config) error {
-	for _, chat := range service.config.Chats {
-		if err := sendMessageToAPI(message, chat, config); err != nil {
+func (service *Service) sendMessageForChatIDs(ctx context.Context, message string, config *Config) error {
+	for i, chat := range service.config.Chats {
+		if err := service.sendMessageToAPI(ctx, message, chat, config); err != nil {
+			if i > 0 {
+				// The message has already been sent to the previous chats
+				return retry.Partial(err)
+			}
 			return err
 		}
 	}
 	return nil
 }
 
+func (service *Service) buildMessageRequests(ctx context.Context, message string, config *Config) ([]*http.Request, error) {
+	client := &Client{token: config.Token, httpClient: service.GetHTTPClient()}
+	requests := make([]*http.Request, 0, len(service.config.Chats))
+	for _, chat := range service.config.Chats {
+		payload := createSendMessagePayload(message, chat, config)
+		req, err := client.NewSendMessageRequest(ctx, &payload)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+func (service *Service) sendFilesForChatIDs(ctx context.Context, files []types.Attachment, config *Config) error {
+	client := &Client{token: config.Token, httpClient: service.GetHTTPClient()}
+	sentFiles := 0
+	for _, chat := range config.Chats {
+		for _, file := range files {
+			sent, err := client.SendFileContext(ctx, chat, file, !config.Notification)
+			if err != nil {
+				if sentFiles > 0 {
+					// The previous files have already been sent
+					return retry.Partial(err)
+				}
+				return err
+			}
+			sentFiles++
+			reportMessageID(ctx, sent)
+		}
+	}
+	return nil
+}
+
 // GetConfig returns the Config for the service
 func (service *Service) GetConfig() *Config {
 	return service.config
 }
 
-func sendMessageToAPI(message string, chat string, config *Config) error {
-	client := &Client{token: config.Token}
+func (service *Service) sendMessageToAPI(ctx context.Context, message string, chat string, config *Config) error {
+	client := &Client{token: config.Token, httpClient: service.GetHTTPClient()}
 	payload := createSendMessagePayload(message, chat, config)
-	_, err := client.SendMessage(&payload)
-	return err
+	sent, err := client.SendMessageContext(ctx, &payload)
+	if err != nil {
+		return err
+	}
+	reportMessageID(ctx, sent)
+	return nil
+}
+
+// reportMessageID reports the ID of the sent message, if it was included in the API response
+func reportMessageID(ctx context.Context, sent *Message) {
+	if sent != nil {
+		types.ReportMessageID(ctx, strconv.FormatInt(sent.MessageID, 10))
+	}
 }
@@ -1,14 +1,30 @@
 package telegram
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
 )
 
 // Client for Telegram API
 type Client struct {
-	token string
+	token      string
+	httpClient *http.Client
+}
+
+// jsonClient returns a JSON client using the http.Client of the Client, or http.DefaultClient if it has none
+func (c *Client) jsonClient() jsonclient.Client {
+	if c.httpClient == nil {
+		return jsonclient.DefaultClient
+	}
+	return jsonclient.NewWithHTTPClient(c.httpClient)
 }
 
 func (c *Client) apiURL(endpoint string) string {
@@ -18,7 +34,7 @@ func (c *Client) apiURL(endpoint string) string {
 // GetBotInfo returns the bot User info
 func (c *Client) GetBotInfo() (*User, error) {
 	response := &userResponse{}
-	err := jsonclient.Get(c.apiURL("getMe"), response)
+	err := c.jsonClient().Get(c.apiURL("getMe"), response)
 
 	if !response.OK {
 		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
@@ -37,7 +53,7 @@ func (c *Client) GetUpdates(offset int, limit int, timeout int, allowedUpdates [
 		AllowedUpdates: allowedUpdates,
 	}
 	response := &updatesResponse{}
-	err := jsonclient.Post(c.apiURL("getUpdates"), request, response)
+	err := c.jsonClient().Post(c.apiURL("getUpdates"), request, response)
 
 	if !response.OK {
 		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
@@ -48,17 +64,102 @@ func (c *Client) GetUpdates(offset int, limit int, timeout int, allowedUpdates [
 
 // SendMessage sends the specified Message
 func (c *Client) SendMessage(message *SendMessagePayload) (*Message, error) {
+	return c.SendMessageContext(context.Background(), message)
+}
+
+// SendMessageContext sends the specified Message, aborting the request if ctx is done
+func (c *Client) SendMessageContext(ctx context.Context, message *SendMessagePayload) (*Message, error) {
+	req, err := c.NewSendMessageRequest(ctx, message)
+	if err != nil {
+		return nil, err
+	}
 
 	response := &messageResponse{}
-	err := jsonclient.Post(c.apiURL("sendMessage"), message, response)
+	err = c.jsonClient().Do(req, response)
 
 	if !response.OK {
-		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
+		if errRes := GetErrorResponse(jsonclient.ErrorBody(err)); errRes != nil {
+			return nil, errRes
+		}
+		if jsonErr, isHTTPError := err.(jsonclient.Error); isHTTPError && jsonErr.StatusCode < 400 {
+			// The server accepted the request, but the response could not be parsed
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+// NewSendMessageRequest returns the request that SendMessageContext makes for the specified Message, without sending it
+func (c *Client) NewSendMessageRequest(ctx context.Context, message *SendMessagePayload) (*http.Request, error) {
+	return c.jsonClient().NewRequest(ctx, http.MethodPost, c.apiURL("sendMessage"), message)
+}
+
+// SendFileContext uploads the attachment to the chat, using sendPhoto for photos and sendDocument for other files,
+// aborting the request if ctx is done
+func (c *Client) SendFileContext(ctx context.Context, chat string, file types.Attachment, disableNotification bool) (*Message, error) {
+	endpoint, field := "sendDocument", "document"
+	if isPhoto(file) {
+		endpoint, field = "sendPhoto", "photo"
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	chatID, threadID := parseChat(chat)
+	fields := map[string]string{
+		"chat_id":              chatID,
+		"disable_notification": strconv.FormatBool(disableNotification),
+	}
+	if threadID != nil {
+		fields["message_thread_id"] = strconv.Itoa(*threadID)
+	}
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	part, err := writer.CreateFormFile(field, file.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(file.Content); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL(endpoint), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	response := &messageResponse{}
+	err = c.jsonClient().Do(req, response)
+
+	if !response.OK {
+		if errRes := GetErrorResponse(jsonclient.ErrorBody(err)); errRes != nil {
+			return nil, errRes
+		}
+		return nil, err
 	}
 
 	return response.Result, nil
 }
 
+// isPhoto returns whether the attachment is an image in one of the formats that telegram accepts as a photo
+func isPhoto(file types.Attachment) bool {
+	switch file.MediaType() {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	}
+	return false
+}
+
 // GetErrorResponse retrieves the error message from a failed request
 func GetErrorResponse(body string) error {
 	response := &errorResponse{}
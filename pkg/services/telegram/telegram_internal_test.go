@@ -5,6 +5,9 @@ import (
 	"errors"
 	"log"
 	"net/url"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -89,6 +92,30 @@ var _ = Describe("the telegram service", func() {
 
 		})
 	})
+
+	Describe("rendering message items", func() {
+		items := []types.MessageItem{
+			{
+				Text:      "Disk <almost> full",
+				Level:     types.Warning,
+				Timestamp: time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+				Fields:    []types.Field{{Key: "host", Value: "db & cache"}},
+			},
+			{Text: "Second"},
+		}
+		rendered := createItemsHTML(items)
+
+		It("should include the level and timestamp", func() {
+			Expect(rendered).To(ContainSubstring("<b>Warning</b> <i>2022-03-04T05:06:07Z</i>"))
+		})
+		It("should escape the item text and fields", func() {
+			Expect(rendered).To(ContainSubstring("Disk &lt;almost&gt; full"))
+			Expect(rendered).To(ContainSubstring("<b>host:</b> db &amp; cache"))
+		})
+		It("should separate the items", func() {
+			Expect(rendered).To(HaveSuffix("\n\nSecond"))
+		})
+	})
 })
 
 func getPayloadFromURL(testURL string, message string, logger *log.Logger) (SendMessagePayload, error) {
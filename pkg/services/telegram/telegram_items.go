@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// levelIcons are the emojis used for indicating the message item levels
+var levelIcons = [types.MessageLevelCount]string{
+	types.Debug:   "🔍",
+	types.Info:    "ℹ️",
+	types.Warning: "⚠️",
+	types.Error:   "❗",
+}
+
+// createItemsHTML renders the message items using the subset of HTML supported by the Telegram bot API
+func createItemsHTML(items []types.MessageItem) string {
+	sb := strings.Builder{}
+
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+
+		var header []string
+		if item.Level != types.Unknown && item.Level < types.MessageLevel(types.MessageLevelCount) {
+			header = append(header, fmt.Sprintf("%s <b>%s</b>", levelIcons[item.Level], item.Level.String()))
+		}
+		if !item.Timestamp.IsZero() {
+			header = append(header, "<i>"+item.Timestamp.UTC().Format(time.RFC3339)+"</i>")
+		}
+		if len(header) > 0 {
+			sb.WriteString(strings.Join(header, " ") + "\n")
+		}
+
+		sb.WriteString(html.EscapeString(item.Text))
+
+		for _, field := range item.Fields {
+			sb.WriteString(fmt.Sprintf("\n<b>%s:</b> %s", html.EscapeString(field.Key), html.EscapeString(field.Value)))
+		}
+	}
+
+	return sb.String()
+}
@@ -5,6 +5,7 @@ import (
 	"html"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // SendMessagePayload is the notification payload for the telegram notification service
@@ -35,13 +36,7 @@ type messageResponse struct {
 }
 
 func createSendMessagePayload(message string, channel string, config *Config) SendMessagePayload {
-	var threadID *int = nil
-	chatID, thread, ok := strings.Cut(channel, ":")
-	if ok {
-		if parsed, err := strconv.Atoi(thread); err == nil {
-			threadID = &parsed
-		}
-	}
+	chatID, threadID := parseChat(channel)
 	payload := SendMessagePayload{
 		Text:                message,
 		ID:                  chatID,
@@ -62,23 +57,55 @@ func createSendMessagePayload(message string, channel string, config *Config) Se
 	}
 
 	// only HTML parse mode is supported for titles
-	if parseMode == ParseModes.HTML {
+	if parseMode == ParseModes.HTML && config.Title != "" {
 		payload.Text = fmt.Sprintf("<b>%v</b>\n%v", html.EscapeString(config.Title), message)
 	}
 
 	return payload
 }
 
+// parseChat splits a chat in the format chat_id[:thread_id] into its chat ID and thread ID. The thread ID is nil if it
+// is missing or not numeric.
+func parseChat(channel string) (chatID string, threadID *int) {
+	chatID, thread, ok := strings.Cut(channel, ":")
+	if ok {
+		if parsed, err := strconv.Atoi(thread); err == nil {
+			threadID = &parsed
+		}
+	}
+	return chatID, threadID
+}
+
 type errorResponse struct {
-	OK          bool   `json:"ok"`
-	ErrorCode   int    `json:"error_code"`
-	Description string `json:"description"`
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *responseParameters `json:"parameters,omitempty"`
+}
+
+// responseParameters contains information about why a request was unsuccessful
+type responseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
 }
 
 func (e *errorResponse) Error() string {
 	return e.Description
 }
 
+// HTTPStatus returns the error code, which corresponds to the HTTP status code of the response
+func (e *errorResponse) HTTPStatus() int {
+	return e.ErrorCode
+}
+
+// RetryAfter returns the delay requested by the server when the request was rate limited
+func (e *errorResponse) RetryAfter() time.Duration {
+	if e.Parameters == nil {
+		return 0
+	}
+	return time.Duration(e.Parameters.RetryAfter) * time.Second
+}
+
 type userResponse struct {
 	OK     bool `json:"ok"`
 	Result User `json:"result"`
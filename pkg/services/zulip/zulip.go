@@ -1,6 +1,7 @@
 package zulip
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -23,6 +25,47 @@ const (
 
 // Send a notification message to Zulip
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Zulip, aborting the request if ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.doSend(ctx, config, message)
+}
+
+// SendMarkdownContext sends the Markdown message to Zulip, which renders it natively, aborting the request if
+// ctx is done
+func (service *Service) SendMarkdownContext(ctx context.Context, message string, params *types.Params) error {
+	return service.SendContext(ctx, message, params)
+}
+
+// BuildRequests returns the request that SendContext would make for the message, without sending it
+func (service *Service) BuildRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := service.buildRequest(ctx, config, message)
+	if err != nil {
+		return nil, err
+	}
+	return []*http.Request{req}, nil
+}
+
+// BuildMarkdownRequests returns the request that SendMarkdownContext would make for the message, without sending it
+func (service *Service) BuildMarkdownRequests(ctx context.Context, message string, params *types.Params) ([]*http.Request, error) {
+	return service.BuildRequests(ctx, message, params)
+}
+
+// messageConfig returns the config for sending the message, with the stream and topic params applied, or an error if
+// the message or topic is too long
+func (service *Service) messageConfig(message string, params *types.Params) (*Config, error) {
 	// Clone the config because we might modify stream and/or
 	// topic with values from the parameters and they should only
 	// change this Send().
@@ -41,16 +84,16 @@ func (service *Service) Send(message string, params *types.Params) error {
 	topicLength := len([]rune(config.Topic))
 
 	if topicLength > topicMaxLength {
-		return fmt.Errorf(string(TopicTooLong), topicMaxLength, topicLength)
+		return nil, fmt.Errorf(string(TopicTooLong), topicMaxLength, topicLength)
 	}
 
 	messageSize := len(message)
 
 	if messageSize > contentMaxSize {
-		return fmt.Errorf("message exceeds max size (%d bytes): was %d bytes", contentMaxSize, messageSize)
+		return nil, fmt.Errorf("message exceeds max size (%d bytes): was %d bytes", contentMaxSize, messageSize)
 	}
 
-	return service.doSend(config, message)
+	return config, nil
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -65,22 +108,39 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	return nil
 }
 
-func (service *Service) doSend(config *Config, message string) error {
-	apiURL := service.getAPIURL(config)
-	payload := CreatePayload(config, message)
-	res, err := http.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(payload.Encode()))
+func (service *Service) doSend(ctx context.Context, config *Config, message string) error {
+	req, err := service.buildRequest(ctx, config, message)
+	if err != nil {
+		return err
+	}
+
+	res, err := service.GetHTTPClient().Do(req)
+	if err == nil {
+		defer res.Body.Close()
+	}
 
 	if err == nil && res.StatusCode != http.StatusOK {
-		err = fmt.Errorf("response status code %s", res.Status)
+		err = retry.NewResponseError(res, fmt.Errorf("response status code %s", res.Status))
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to send zulip message: %s", err)
+		return fmt.Errorf("failed to send zulip message: %w", err)
 	}
 
 	return nil
 }
 
+func (service *Service) buildRequest(ctx context.Context, config *Config, message string) (*http.Request, error) {
+	apiURL := service.getAPIURL(config)
+	payload := CreatePayload(config, message)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(payload.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send zulip message: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
 func (service *Service) getAPIURL(config *Config) string {
 	return (&url.URL{
 		User:   url.UserPassword(config.BotMail, config.BotKey),
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrAttachmentsUnsupported is returned by services that cannot upload the attachments of a message
+var ErrAttachmentsUnsupported = errors.New("attachments unsupported")
+
+// Attachment is a file that is sent together with a message item
+type Attachment struct {
+	// Name is the file name of the attachment, as presented by the service
+	Name string
+	// ContentType is the MIME type of the content. If empty, it is detected from the name or the content itself.
+	ContentType string
+	// Content is the data of the file
+	Content []byte
+}
+
+// NewAttachment creates an attachment with the content type detected from the name or content
+func NewAttachment(name string, content []byte) Attachment {
+	return Attachment{
+		Name:    name,
+		Content: content,
+	}
+}
+
+// ReadAttachment creates an attachment using all the content that can be read from reader
+func ReadAttachment(name string, reader io.Reader) (Attachment, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return Attachment{}, err
+	}
+	return NewAttachment(name, content), nil
+}
+
+// LoadAttachment creates an attachment from the file at path, using the base name of the file as its name
+func LoadAttachment(path string) (Attachment, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	return NewAttachment(filepath.Base(path), content), nil
+}
+
+// MediaType returns the content type of the attachment, detecting it from the name or the content if it is not set
+func (a Attachment) MediaType() string {
+	if a.ContentType != "" {
+		return a.ContentType
+	}
+	if byExtension := mime.TypeByExtension(filepath.Ext(a.Name)); byExtension != "" {
+		return byExtension
+	}
+	return http.DetectContentType(a.Content)
+}
+
+// IsImage returns whether the media type of the attachment is an image
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MediaType(), "image/")
+}
+
+// ItemsAttachments returns the attachments of all the message items, in order
+func ItemsAttachments(items []MessageItem) []Attachment {
+	var attachments []Attachment
+	for _, item := range items {
+		attachments = append(attachments, item.Attachments...)
+	}
+	return attachments
+}
@@ -0,0 +1,8 @@
+package types
+
+import "context"
+
+// ContextSender is the interface needed to implement to send notifications that can be cancelled using a context
+type ContextSender interface {
+	SendContext(ctx context.Context, message string, params *Params) error
+}
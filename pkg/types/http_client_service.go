@@ -0,0 +1,8 @@
+package types
+
+import "net/http"
+
+// HTTPClientService is the interface for services that can use a custom client for their HTTP requests
+type HTTPClientService interface {
+	SetHTTPClient(client *http.Client)
+}
@@ -0,0 +1,9 @@
+package types
+
+import "context"
+
+// MarkdownSender is the interface needed to implement to send Markdown messages using the native formatting of the
+// service. Services that does not implement it are sent the Markdown messages converted to plain text.
+type MarkdownSender interface {
+	SendMarkdownContext(ctx context.Context, markdown string, params *Params) error
+}
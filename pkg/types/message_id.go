@@ -0,0 +1,38 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+type messageIDsKey struct{}
+
+// MessageIDs collects the IDs that providers assign to the sent messages
+type MessageIDs struct {
+	lock sync.Mutex
+	ids  []string
+}
+
+// WithMessageIDs returns a context that collects the message IDs reported by services using ReportMessageID
+func WithMessageIDs(ctx context.Context) (context.Context, *MessageIDs) {
+	ids := &MessageIDs{}
+	return context.WithValue(ctx, messageIDsKey{}, ids), ids
+}
+
+// ReportMessageID records the ID that the provider assigned to a sent message, if ctx collects message IDs
+func ReportMessageID(ctx context.Context, id string) {
+	ids, ok := ctx.Value(messageIDsKey{}).(*MessageIDs)
+	if !ok || id == "" {
+		return
+	}
+	ids.lock.Lock()
+	ids.ids = append(ids.ids, id)
+	ids.lock.Unlock()
+}
+
+// IDs returns the reported message IDs, in the order they were reported
+func (m *MessageIDs) IDs() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]string(nil), m.ids...)
+}
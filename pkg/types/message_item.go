@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -39,12 +40,24 @@ func (level MessageLevel) String() string {
 	return messageLevelStrings[level]
 }
 
+// ParseMessageLevel returns the message level with the specified name, ignoring case
+func ParseMessageLevel(name string) (MessageLevel, error) {
+	for level, levelString := range messageLevelStrings {
+		if strings.EqualFold(name, levelString) {
+			return MessageLevel(level), nil
+		}
+	}
+	return Unknown, fmt.Errorf("unknown message level %q", name)
+}
+
 // MessageItem is an entry in a notification being sent by a service
 type MessageItem struct {
 	Text      string
 	Timestamp time.Time
 	Level     MessageLevel
 	Fields    []Field
+	// Attachments are files sent together with the item, by services that support uploads
+	Attachments []Attachment
 }
 
 // WithField appends the key/value pair to the message items fields
@@ -8,3 +8,8 @@ type MessageLimit struct {
 	// Maximum number of chunks (including the last chunk for meta data)
 	ChunkCount int
 }
+
+// MessageLimitService is the interface for services that declares the payload limits of their upstream APIs
+type MessageLimitService interface {
+	MessageLimit() MessageLimit
+}
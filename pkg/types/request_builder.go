@@ -0,0 +1,22 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrDryRunUnsupported is returned by dry runs for services that cannot build their requests without sending them
+var ErrDryRunUnsupported = errors.New("dry runs are not supported")
+
+// RequestBuilder is the interface needed to implement to support dry runs, which reports the requests that would be
+// made when sending a message, without making them. The returned requests must not be sent by the caller.
+type RequestBuilder interface {
+	BuildRequests(ctx context.Context, message string, params *Params) ([]*http.Request, error)
+}
+
+// MarkdownRequestBuilder is the interface needed to implement to support dry runs of Markdown messages, for services
+// that implement MarkdownSender
+type MarkdownRequestBuilder interface {
+	BuildMarkdownRequests(ctx context.Context, markdown string, params *Params) ([]*http.Request, error)
+}
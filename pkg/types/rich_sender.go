@@ -1,6 +1,14 @@
 package types
 
+import "context"
+
 // RichSender is the interface needed to implement to send rich notifications
 type RichSender interface {
 	SendItems(items []MessageItem, params Params) error
 }
+
+// RichContextSender is the interface needed to implement to send rich notifications that can be cancelled using a
+// context
+type RichContextSender interface {
+	SendItemsContext(ctx context.Context, items []MessageItem, params Params) error
+}
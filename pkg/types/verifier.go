@@ -0,0 +1,8 @@
+package types
+
+import "context"
+
+// Verifier is the interface for services that can check their credentials with the server without sending a message
+type Verifier interface {
+	Verify(ctx context.Context) error
+}
@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Options configures the transport of the HTTP clients created using New
+type Options struct {
+	// ProxyURL is the URL of the proxy used for all requests. If empty, the proxy is taken from the environment.
+	ProxyURL string `yaml:"proxy" toml:"proxy"`
+	// CAFile is the path to a PEM encoded bundle of CA certificates that are trusted in addition to the system ones
+	CAFile string `yaml:"caFile" toml:"caFile"`
+	// CertFile is the path to a PEM encoded client certificate, used for mutual TLS together with KeyFile
+	CertFile string `yaml:"certFile" toml:"certFile"`
+	// KeyFile is the path to the PEM encoded private key of CertFile
+	KeyFile string `yaml:"keyFile" toml:"keyFile"`
+	// InsecureSkipVerify disables the verification of the server certificates
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify" toml:"insecureSkipVerify"`
+}
+
+// New creates an HTTP client with a transport configured according to options
+func New(options Options) (*http.Client, error) {
+	transport, err := NewTransport(options)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// NewTransport creates an HTTP transport configured according to options, using the settings of
+// http.DefaultTransport for everything else
+func NewTransport(options Options) (*http.Transport, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := options.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+func (options Options) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if options.CAFile != "" {
+		bundle, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %v", options.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.CertFile != "" || options.KeyFile != "" {
+		if options.CertFile == "" || options.KeyFile == "" {
+			return nil, errors.New("both a client certificate and key file are required for mutual TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHTTPClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP Client Suite")
+}
+
+var _ = Describe("the HTTP client", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	writeServerCA := func() string {
+		path := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+		Expect(os.WriteFile(path, pem.EncodeToMemory(block), 0600)).To(Succeed())
+		return path
+	}
+
+	It("should not trust unknown server certificates by default", func() {
+		client, err := New(Options{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Get(server.URL)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should trust the certificates in the CA bundle", func() {
+		client, err := New(Options{CAFile: writeServerCA()})
+		Expect(err).NotTo(HaveOccurred())
+		res, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+	})
+
+	It("should skip the verification when insecure", func() {
+		client, err := New(Options{InsecureSkipVerify: true})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should use the proxy for all requests", func() {
+		transport, err := NewTransport(Options{ProxyURL: "http://proxy.example.com:3128"})
+		Expect(err).NotTo(HaveOccurred())
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		proxyURL, err := transport.Proxy(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxyURL.String()).To(Equal("http://proxy.example.com:3128"))
+	})
+
+	It("should return an error for invalid options", func() {
+		for _, options := range []Options{
+			{ProxyURL: "://proxy"},
+			{CAFile: filepath.Join(GinkgoT().TempDir(), "missing.pem")},
+			{CertFile: "cert.pem"},
+			{KeyFile: "key.pem"},
+		} {
+			_, err := New(options)
+			Expect(err).To(HaveOccurred(), "%+v", options)
+		}
+	})
+
+	It("should return an error if the CA bundle contains no certificates", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "empty.pem")
+		Expect(os.WriteFile(path, []byte("not a certificate"), 0600)).To(Succeed())
+		_, err := New(Options{CAFile: path})
+		Expect(err).To(MatchError(ContainSubstring("no certificates found")))
+	})
+})
@@ -1,12 +1,16 @@
 package jsonclient
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Error contains additional http/JSON details
 type Error struct {
 	StatusCode int
 	Body       string
 	err        error
+	retryAfter time.Duration
 }
 
 func (je Error) Error() string {
@@ -20,6 +24,21 @@ func (je Error) String() string {
 	return je.err.Error()
 }
 
+// Unwrap returns the underlying error
+func (je Error) Unwrap() error {
+	return je.err
+}
+
+// HTTPStatus returns the response status code
+func (je Error) HTTPStatus() int {
+	return je.StatusCode
+}
+
+// RetryAfter returns the delay requested by the server using the Retry-After header
+func (je Error) RetryAfter() time.Duration {
+	return je.retryAfter
+}
+
 // ErrorBody returns the request body from an Error
 func ErrorBody(e error) string {
 	if jsonError, ok := e.(Error); ok {
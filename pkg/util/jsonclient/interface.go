@@ -1,10 +1,17 @@
 package jsonclient
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type Client interface {
 	Get(url string, response interface{}) error
+	GetContext(ctx context.Context, url string, response interface{}) error
 	Post(url string, request interface{}, response interface{}) error
+	PostContext(ctx context.Context, url string, request interface{}, response interface{}) error
+	NewRequest(ctx context.Context, method string, url string, request interface{}) (*http.Request, error)
+	Do(req *http.Request, response interface{}) error
 	Headers() http.Header
 	ErrorResponse(err error, response interface{}) bool
 }
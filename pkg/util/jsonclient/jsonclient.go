@@ -2,10 +2,13 @@ package jsonclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
 )
 
 // ContentType is the default mime type for JSON
@@ -19,11 +22,22 @@ func Get(url string, response interface{}) error {
 	return DefaultClient.Get(url, response)
 }
 
+// GetContext fetches url using GET and unmarshals into the passed response using DefaultClient, aborting if ctx is done
+func GetContext(ctx context.Context, url string, response interface{}) error {
+	return DefaultClient.GetContext(ctx, url, response)
+}
+
 // Post sends request as JSON and unmarshals the response JSON into the supplied struct using DefaultClient
 func Post(url string, request interface{}, response interface{}) error {
 	return DefaultClient.Post(url, request, response)
 }
 
+// PostContext sends request as JSON and unmarshals the response JSON into the supplied struct using DefaultClient,
+// aborting if ctx is done
+func PostContext(ctx context.Context, url string, request interface{}, response interface{}) error {
+	return DefaultClient.PostContext(ctx, url, request, response)
+}
+
 // Client is a JSON wrapper around http.Client
 type client struct {
 	httpClient *http.Client
@@ -53,7 +67,17 @@ func (c *client) Headers() http.Header {
 
 // Get fetches url using GET and unmarshals into the passed response
 func (c *client) Get(url string, response interface{}) error {
-	res, err := c.httpClient.Get(url)
+	return c.GetContext(context.Background(), url, response)
+}
+
+// GetContext fetches url using GET and unmarshals into the passed response, aborting if ctx is done
+func (c *client) GetContext(ctx context.Context, url string, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -63,6 +87,28 @@ func (c *client) Get(url string, response interface{}) error {
 
 // Post sends request as JSON and unmarshals the response JSON into the supplied struct
 func (c *client) Post(url string, request interface{}, response interface{}) error {
+	return c.PostContext(context.Background(), url, request, response)
+}
+
+// PostContext sends request as JSON and unmarshals the response JSON into the supplied struct, aborting if ctx is done
+func (c *client) PostContext(ctx context.Context, url string, request interface{}, response interface{}) error {
+	req, err := c.NewRequest(ctx, http.MethodPost, url, request)
+	if err != nil {
+		return err
+	}
+
+	var res *http.Response
+	res, err = c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending payload: %w", err)
+	}
+
+	return parseResponse(res, response)
+}
+
+// NewRequest returns a request with request serialized as JSON as its body, and the default headers set, without
+// sending it. String requests are used as the body as is.
+func (c *client) NewRequest(ctx context.Context, method string, url string, request interface{}) (*http.Request, error) {
 	var err error
 	var body []byte
 
@@ -72,23 +118,35 @@ func (c *client) Post(url string, request interface{}, response interface{}) err
 	} else {
 		body, err = json.MarshalIndent(request, "", c.indent)
 		if err != nil {
-			return fmt.Errorf("error creating payload: %w", err)
+			return nil, fmt.Errorf("error creating payload: %w", err)
 		}
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	for key, val := range c.headers {
 		req.Header.Set(key, val[0])
 	}
 
-	var res *http.Response
-	res, err = c.httpClient.Do(req)
+	return req, nil
+}
+
+// Do sends the prepared request, using the default headers for any headers that it does not already set, and
+// unmarshals the response JSON into the supplied struct. It allows sending request bodies other than JSON, such as
+// file uploads, while handling the response the same way as the other methods.
+func (c *client) Do(req *http.Request, response interface{}) error {
+	for key, val := range c.headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, val[0])
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("error sending payload: %w", err)
+		return fmt.Errorf("error sending request: %w", err)
 	}
 
 	return parseResponse(res, response)
@@ -123,6 +181,7 @@ func parseResponse(res *http.Response, response interface{}) error {
 			StatusCode: res.StatusCode,
 			Body:       string(body),
 			err:        err,
+			retryAfter: retry.ParseRetryAfter(res.Header.Get("Retry-After")),
 		}
 	}
 
@@ -1,8 +1,11 @@
 package jsonclient_test
 
 import (
+	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
@@ -80,6 +83,14 @@ var _ = Describe("JSONClient", func() {
 			Expect(err).To(MatchError("got HTTP 404 Not Found"))
 		})
 
+		It("should not send the request if the context has been cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := jsonclient.PostContext(ctx, server.URL(), &mockRequest{}, &mockResponse{})
+			Expect(server.ReceivedRequests()).Should(HaveLen(0))
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
 		It("should return error on invalid request", func() {
 			server.AppendHandlers(ghttp.VerifyRequest("POST", "/"))
 			err := jsonclient.Post(server.URL(), func() {}, &mockResponse{})
@@ -101,6 +112,39 @@ var _ = Describe("JSONClient", func() {
 		})
 	})
 
+	Describe("Do", func() {
+		It("should send the request as is and deserialize the response", func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/upload"),
+				ghttp.VerifyContentType("text/plain"),
+				ghttp.VerifyBody([]byte("file content")),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, &mockResponse{Status: "Uploaded"})),
+			)
+
+			req, err := http.NewRequest(http.MethodPut, server.URL()+"/upload", strings.NewReader("file content"))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "text/plain")
+
+			res := &mockResponse{}
+			Expect(jsonclient.DefaultClient.Do(req, res)).To(Succeed())
+			Expect(res.Status).To(Equal("Uploaded"))
+		})
+	})
+
+	Describe("NewRequest", func() {
+		It("should build the request without sending it", func() {
+			req, err := jsonclient.DefaultClient.NewRequest(context.Background(), http.MethodPut, server.URL()+"/put", &mockRequest{Number: 5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.Method).To(Equal(http.MethodPut))
+			Expect(req.Header.Get("Content-Type")).To(Equal(jsonclient.ContentType))
+
+			body, err := req.GetBody()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.ReadAll(body)).To(MatchJSON(`{"Number":5}`))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
 	AfterEach(func() {
 		//shut down the server between tests
 		server.Close()
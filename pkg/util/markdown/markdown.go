@@ -0,0 +1,305 @@
+// Package markdown converts Markdown messages into the formats used by the notification services. It supports the
+// commonly used subset of Markdown: headings, paragraphs, lists, block quotes, code blocks, horizontal rules, and
+// bold, italic, strikethrough, code and link spans.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	codeBlock
+	quoteBlock
+	listBlock
+	ruleBlock
+)
+
+// block is a block level element of a Markdown document
+type block struct {
+	kind blockKind
+	// level is the level of headings
+	level int
+	// inlines is the content of paragraphs and headings
+	inlines []inline
+	// code is the content of code blocks
+	code string
+	// children are the blocks of block quotes
+	children []block
+	// items are the items of lists
+	items []listItem
+}
+
+// listItem is an item of a list, where depth is the nesting level of the item
+type listItem struct {
+	inlines []inline
+	depth   int
+	ordered bool
+	number  int
+}
+
+type inlineKind int
+
+const (
+	textInline inlineKind = iota
+	strongInline
+	emphasisInline
+	strikeInline
+	codeInline
+	linkInline
+)
+
+// inline is a span of text within a block, which for formatted spans contains the formatted spans
+type inline struct {
+	kind     inlineKind
+	text     string
+	url      string
+	children []inline
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fencePattern    = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	rulePattern     = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	quotePattern    = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	bulletPattern   = regexp.MustCompile(`^([ \t]*)[-*+][ \t]+(.*)$`)
+	numberedPattern = regexp.MustCompile(`^([ \t]*)(\d{1,9})[.)][ \t]+(.*)$`)
+)
+
+// parse returns the blocks of the Markdown text
+func parse(text string) []block {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var blocks []block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			fence := fencePattern.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			blocks = append(blocks, block{kind: codeBlock, code: strings.Join(code, "\n")})
+
+		case headingPattern.MatchString(line):
+			match := headingPattern.FindStringSubmatch(line)
+			blocks = append(blocks, block{kind: headingBlock, level: len(match[1]), inlines: parseInlines(match[2])})
+			i++
+
+		case rulePattern.MatchString(line):
+			blocks = append(blocks, block{kind: ruleBlock})
+			i++
+
+		case quotePattern.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			blocks = append(blocks, block{kind: quoteBlock, children: parse(strings.Join(quoted, "\n"))})
+
+		case isListItem(line):
+			var items []listItem
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				if item, isItem := parseListItem(lines[i]); isItem {
+					items = append(items, item)
+				} else if len(items) > 0 {
+					// Lines that are not items continue the text of the previous item
+					last := &items[len(items)-1]
+					last.inlines = append(last.inlines, inline{kind: textInline, text: "\n"})
+					last.inlines = append(last.inlines, parseInlines(strings.TrimSpace(lines[i]))...)
+				}
+			}
+			blocks = append(blocks, block{kind: listBlock, items: items})
+
+		default:
+			var paragraph []string
+			for ; i < len(lines) && !startsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			blocks = append(blocks, block{kind: paragraphBlock, inlines: parseInlines(strings.Join(paragraph, "\n"))})
+		}
+	}
+
+	return blocks
+}
+
+// startsBlock returns whether the line ends a paragraph, by being blank or starting another block
+func startsBlock(line string) bool {
+	return strings.TrimSpace(line) == "" ||
+		fencePattern.MatchString(line) ||
+		headingPattern.MatchString(line) ||
+		rulePattern.MatchString(line) ||
+		quotePattern.MatchString(line) ||
+		isListItem(line)
+}
+
+func isListItem(line string) bool {
+	return bulletPattern.MatchString(line) || numberedPattern.MatchString(line)
+}
+
+func parseListItem(line string) (listItem, bool) {
+	if match := bulletPattern.FindStringSubmatch(line); match != nil {
+		return listItem{depth: indentDepth(match[1]), inlines: parseInlines(match[2])}, true
+	}
+	if match := numberedPattern.FindStringSubmatch(line); match != nil {
+		number, _ := strconv.Atoi(match[2])
+		return listItem{depth: indentDepth(match[1]), ordered: true, number: number, inlines: parseInlines(match[3])}, true
+	}
+	return listItem{}, false
+}
+
+// indentDepth returns the nesting level of a list item from its indentation, where a tab or two spaces is a level
+func indentDepth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "  ")) / 2
+}
+
+// parseInlines returns the inline spans of the text
+func parseInlines(text string) []inline {
+	var inlines []inline
+	var plain strings.Builder
+	runes := []rune(text)
+
+	flush := func() {
+		if plain.Len() > 0 {
+			inlines = append(inlines, inline{kind: textInline, text: plain.String()})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\\' && i+1 < len(runes) && isPunctuation(runes[i+1]) {
+			plain.WriteRune(runes[i+1])
+			i++
+			continue
+		}
+
+		if span, length, found := parseSpan(runes, i); found {
+			flush()
+			inlines = append(inlines, span)
+			i += length - 1
+			continue
+		}
+
+		plain.WriteRune(r)
+	}
+
+	flush()
+	return inlines
+}
+
+// parseSpan parses a formatted span starting at index i, returning it together with its length in runes
+func parseSpan(runes []rune, i int) (inline, int, bool) {
+	rest := string(runes[i:])
+
+	switch runes[i] {
+	case '`':
+		ticks := countRun(runes, i, '`')
+		delimiter := strings.Repeat("`", ticks)
+		if end := strings.Index(rest[ticks:], delimiter); end >= 0 {
+			code := rest[ticks : ticks+end]
+			if trimmed := strings.TrimSpace(code); trimmed != "" {
+				code = trimmed
+			}
+			return inline{kind: codeInline, text: code}, len([]rune(rest[:ticks+end+ticks])), true
+		}
+
+	case '*', '_':
+		if runes[i] == '_' && i > 0 && isWordRune(runes[i-1]) {
+			// Underscores within words, like in snake_case, are not emphasis
+			break
+		}
+		delimiter := string(runes[i])
+		kind := emphasisInline
+		if countRun(runes, i, runes[i]) >= 2 {
+			delimiter += delimiter
+			kind = strongInline
+		}
+		if content, length, found := delimited(rest, delimiter); found {
+			return inline{kind: kind, children: parseInlines(content)}, length, true
+		}
+
+	case '~':
+		if strings.HasPrefix(rest, "~~") {
+			if content, length, found := delimited(rest, "~~"); found {
+				return inline{kind: strikeInline, children: parseInlines(content)}, length, true
+			}
+		}
+
+	case '[':
+		if match := linkPattern.FindStringSubmatch(rest); match != nil {
+			return inline{kind: linkInline, url: match[2], children: parseInlines(match[1])}, len([]rune(match[0])), true
+		}
+
+	case '<':
+		if match := autolinkPattern.FindStringSubmatch(rest); match != nil {
+			return inline{kind: linkInline, url: match[1], children: []inline{{kind: textInline, text: match[1]}}},
+				len([]rune(match[0])), true
+		}
+	}
+
+	return inline{}, 0, false
+}
+
+var (
+	linkPattern     = regexp.MustCompile(`^\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(\s*<?([^\s()<>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	autolinkPattern = regexp.MustCompile(`^<((?:https?|mailto|ftp):[^\s<>]+)>`)
+)
+
+// delimited returns the content between the opening delimiter at the start of text and the closing delimiter, and the
+// length of the whole span in runes. The content may not start or end with whitespace.
+func delimited(text string, delimiter string) (string, int, bool) {
+	body := text[len(delimiter):]
+	if body == "" || strings.HasPrefix(body, " ") || strings.HasPrefix(body, "\n") {
+		return "", 0, false
+	}
+
+	for offset := 0; offset < len(body); {
+		end := strings.Index(body[offset:], delimiter)
+		if end < 0 {
+			return "", 0, false
+		}
+		end += offset
+		content := body[:end]
+		after := body[end+len(delimiter):]
+
+		// The closing delimiter may not be preceded by whitespace, or be a part of a longer run of the same character
+		if content != "" && !strings.HasSuffix(content, " ") && !strings.HasPrefix(after, delimiter[:1]) {
+			if delimiter != "_" || after == "" || !isWordRune([]rune(after)[0]) {
+				return content, len([]rune(text[:len(delimiter)+end+len(delimiter)])), true
+			}
+		}
+		offset = end + 1
+		for offset < len(body) && body[offset] == delimiter[0] {
+			offset++
+		}
+	}
+	return "", 0, false
+}
+
+func countRun(runes []rune, i int, r rune) int {
+	count := 0
+	for ; i+count < len(runes) && runes[i+count] == r; count++ {
+	}
+	return count
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127
+}
+
+func isPunctuation(r rune) bool {
+	return strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", r)
+}
@@ -0,0 +1,125 @@
+package markdown
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMarkdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Markdown Suite")
+}
+
+const document = `# Disk *almost* full
+
+The disk on **web01** is at 95%,
+see [the dashboard](https://example.com/d?a=1&b=2).
+
+- free up space
+  - remove ` + "`/tmp/*.log`" + `
+- ~~restart~~
+
+> Quoted _text_
+
+` + "```" + `
+df -h <mount>
+` + "```"
+
+var _ = Describe("the markdown converters", func() {
+	When("converting to HTML", func() {
+		It("should convert all the supported elements", func() {
+			Expect(ToHTML(document)).To(Equal(`<h1>Disk <em>almost</em> full</h1>
+<p>The disk on <strong>web01</strong> is at 95%,<br>
+see <a href="https://example.com/d?a=1&amp;b=2">the dashboard</a>.</p>
+<ul><li>free up space<ul><li>remove <code>/tmp/*.log</code></li></ul></li><li><del>restart</del></li></ul>
+<blockquote>
+<p>Quoted <em>text</em></p>
+</blockquote>
+<pre><code>df -h &lt;mount&gt;</code></pre>`))
+		})
+
+		It("should keep the numbering of ordered lists", func() {
+			Expect(ToHTML("3. third\n4. fourth\n- other")).To(Equal(
+				`<ol start="3"><li>third</li><li>fourth</li></ol><ul><li>other</li></ul>`))
+		})
+
+		It("should escape HTML in the text", func() {
+			Expect(ToHTML("a <b> & c")).To(Equal("<p>a &lt;b&gt; &amp; c</p>"))
+		})
+	})
+
+	When("converting to limited HTML", func() {
+		It("should only use the supported tags", func() {
+			Expect(ToLimitedHTML(document, TelegramTags)).To(Equal(`<b>Disk <i>almost</i> full</b>
+
+The disk on <b>web01</b> is at 95%,
+see <a href="https://example.com/d?a=1&amp;b=2">the dashboard</a>.
+
+• free up space
+  • remove <code>/tmp/*.log</code>
+• <s>restart</s>
+
+<blockquote>Quoted <i>text</i></blockquote>
+
+<pre>df -h &lt;mount&gt;</pre>`))
+		})
+
+		It("should use plain text for elements without a supported tag", func() {
+			Expect(ToLimitedHTML("~~a~~ `b` **c**\n\n> d", PushoverTags)).To(Equal("a b <b>c</b>\n\n> d"))
+		})
+	})
+
+	When("converting to the slack format", func() {
+		It("should use the mrkdwn syntax", func() {
+			Expect(ToSlack("**a** _b_ ~~c~~ `d` [e](https://example.com) 1 < 2")).
+				To(Equal("*a* _b_ ~c~ `d` <https://example.com|e> 1 &lt; 2"))
+			Expect(ToSlack("## Title\n\n```\na && b\n```")).To(Equal("*Title*\n\n```\na &amp;&amp; b\n```"))
+		})
+
+		It("should not escape the text for Google Chat", func() {
+			Expect(ToGoogleChat("**a** & b")).To(Equal("*a* & b"))
+		})
+	})
+
+	When("converting to plain text", func() {
+		It("should remove the formatting", func() {
+			Expect(ToPlain(document)).To(Equal(`Disk almost full
+
+The disk on web01 is at 95%,
+see the dashboard (https://example.com/d?a=1&b=2).
+
+• free up space
+  • remove /tmp/*.log
+• restart
+
+> Quoted text
+
+df -h <mount>`))
+		})
+
+		It("should not repeat URLs that are used as the link text", func() {
+			Expect(ToPlain("<https://example.com> and [https://example.com](https://example.com)")).
+				To(Equal("https://example.com and https://example.com"))
+		})
+	})
+
+	When("parsing the inline formatting", func() {
+		It("should not treat underscores within words as emphasis", func() {
+			Expect(ToPlain("snake_case_name and _emphasis_")).To(Equal("snake_case_name and emphasis"))
+		})
+
+		It("should keep unmatched delimiters", func() {
+			Expect(ToPlain("2 * 3 = 6, a ** b and [not a link]")).To(Equal("2 * 3 = 6, a ** b and [not a link]"))
+		})
+
+		It("should keep escaped characters as is", func() {
+			Expect(ToPlain(`\*not emphasis\* and \# not a heading`)).To(Equal("*not emphasis* and # not a heading"))
+		})
+
+		It("should support nested formatting", func() {
+			Expect(ToHTML("**bold _and italic_**")).To(Equal("<p><strong>bold <em>and italic</em></strong></p>"))
+		})
+	})
+})
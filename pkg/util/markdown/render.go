@@ -0,0 +1,301 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ToHTML converts the Markdown text to HTML, where line breaks within paragraphs are kept
+func ToHTML(text string) string {
+	return renderHTMLBlocks(parse(text))
+}
+
+func renderHTMLBlocks(blocks []block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.kind {
+		case paragraphBlock:
+			parts = append(parts, "<p>"+renderHTMLInlines(b.inlines)+"</p>")
+		case headingBlock:
+			parts = append(parts, fmt.Sprintf("<h%d>%s</h%d>", b.level, renderHTMLInlines(b.inlines), b.level))
+		case codeBlock:
+			parts = append(parts, "<pre><code>"+html.EscapeString(b.code)+"</code></pre>")
+		case quoteBlock:
+			parts = append(parts, "<blockquote>\n"+renderHTMLBlocks(b.children)+"\n</blockquote>")
+		case listBlock:
+			parts = append(parts, renderHTMLList(b.items))
+		case ruleBlock:
+			parts = append(parts, "<hr>")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// renderHTMLList renders the items as nested lists, starting a new list whenever the depth or list type changes
+func renderHTMLList(items []listItem) string {
+	sb := strings.Builder{}
+	var open []string
+
+	closeList := func() {
+		sb.WriteString("</li></" + open[len(open)-1] + ">")
+		open = open[:len(open)-1]
+	}
+
+	for i, item := range items {
+		tag := "ul"
+		if item.ordered {
+			tag = "ol"
+		}
+
+		depth := item.depth
+		if depth > len(open) {
+			depth = len(open)
+		}
+		for len(open) > depth+1 {
+			closeList()
+		}
+		if len(open) == depth+1 && open[depth] != tag {
+			closeList()
+		}
+
+		if len(open) == depth {
+			if item.ordered && item.number != 1 {
+				sb.WriteString(fmt.Sprintf("<%s start=\"%d\">", tag, item.number))
+			} else {
+				sb.WriteString("<" + tag + ">")
+			}
+			open = append(open, tag)
+		} else if i > 0 {
+			sb.WriteString("</li>")
+		}
+		sb.WriteString("<li>" + renderHTMLInlines(item.inlines))
+	}
+
+	for len(open) > 0 {
+		closeList()
+	}
+	return sb.String()
+}
+
+func renderHTMLInlines(inlines []inline) string {
+	sb := strings.Builder{}
+	for _, span := range inlines {
+		switch span.kind {
+		case textInline:
+			sb.WriteString(strings.ReplaceAll(html.EscapeString(span.text), "\n", "<br>\n"))
+		case strongInline:
+			sb.WriteString("<strong>" + renderHTMLInlines(span.children) + "</strong>")
+		case emphasisInline:
+			sb.WriteString("<em>" + renderHTMLInlines(span.children) + "</em>")
+		case strikeInline:
+			sb.WriteString("<del>" + renderHTMLInlines(span.children) + "</del>")
+		case codeInline:
+			sb.WriteString("<code>" + html.EscapeString(span.text) + "</code>")
+		case linkInline:
+			sb.WriteString(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(span.url), renderHTMLInlines(span.children)))
+		}
+	}
+	return sb.String()
+}
+
+// Tags are the HTML tags supported by a service that only renders a limited set of tags
+type Tags struct {
+	Bold       string
+	Italic     string
+	Strike     string
+	Code       string
+	Pre        string
+	Link       bool
+	Blockquote string
+}
+
+var (
+	// TelegramTags are the tags supported by the Telegram HTML parse mode
+	TelegramTags = Tags{Bold: "b", Italic: "i", Strike: "s", Code: "code", Pre: "pre", Link: true, Blockquote: "blockquote"}
+	// PushoverTags are the tags supported by Pushover HTML messages
+	PushoverTags = Tags{Bold: "b", Italic: "i", Link: true}
+)
+
+// ToLimitedHTML converts the Markdown text to HTML using only the supported inline tags, with blocks separated by
+// blank lines rather than using block tags. Headings are rendered in bold, and lists using bullets or numbers.
+// Elements without a supported tag are rendered as plain text.
+func ToLimitedHTML(text string, tags Tags) string {
+	r := textRenderer{
+		escape: escapeHTML,
+		inline: func(span inline, content string) string {
+			return limitedHTMLInline(span, content, tags)
+		},
+		heading: func(content string) string {
+			return wrapTag(tags.Bold, content)
+		},
+		code: func(code string) string {
+			return wrapTag(tags.Pre, escapeHTML(code))
+		},
+		quote: func(content string) string {
+			if tags.Blockquote == "" {
+				return prefixLines(content, "> ")
+			}
+			return wrapTag(tags.Blockquote, content)
+		},
+	}
+	return r.render(parse(text))
+}
+
+func limitedHTMLInline(span inline, content string, tags Tags) string {
+	switch span.kind {
+	case strongInline:
+		return wrapTag(tags.Bold, content)
+	case emphasisInline:
+		return wrapTag(tags.Italic, content)
+	case strikeInline:
+		return wrapTag(tags.Strike, content)
+	case codeInline:
+		return wrapTag(tags.Code, escapeHTML(span.text))
+	case linkInline:
+		if tags.Link {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(span.url), content)
+		}
+		return plainLink(span, content)
+	}
+	return content
+}
+
+// ToSlack converts the Markdown text to the Slack mrkdwn format
+func ToSlack(text string) string {
+	return slackRenderer(escapeHTML).render(parse(text))
+}
+
+// ToGoogleChat converts the Markdown text to the text formatting of Google Chat, which is the same as the Slack mrkdwn
+// format, except that text is not escaped
+func ToGoogleChat(text string) string {
+	return slackRenderer(func(text string) string { return text }).render(parse(text))
+}
+
+func slackRenderer(escape func(text string) string) textRenderer {
+	return textRenderer{
+		escape: escape,
+		inline: func(span inline, content string) string {
+			switch span.kind {
+			case strongInline:
+				return "*" + content + "*"
+			case emphasisInline:
+				return "_" + content + "_"
+			case strikeInline:
+				return "~" + content + "~"
+			case codeInline:
+				return "`" + escape(span.text) + "`"
+			case linkInline:
+				return "<" + span.url + "|" + content + ">"
+			}
+			return content
+		},
+		heading: func(content string) string {
+			return "*" + content + "*"
+		},
+		code: func(code string) string {
+			return "```\n" + escape(code) + "\n```"
+		},
+		quote: func(content string) string {
+			return prefixLines(content, "> ")
+		},
+	}
+}
+
+// ToPlain converts the Markdown text to plain text, removing the formatting. Links are written as the link text
+// followed by the URL, and lists using bullets or numbers.
+func ToPlain(text string) string {
+	r := textRenderer{
+		escape: func(text string) string { return text },
+		inline: func(span inline, content string) string {
+			switch span.kind {
+			case codeInline:
+				return span.text
+			case linkInline:
+				return plainLink(span, content)
+			}
+			return content
+		},
+		heading: func(content string) string { return content },
+		code:    func(code string) string { return code },
+		quote: func(content string) string {
+			return prefixLines(content, "> ")
+		},
+	}
+	return r.render(parse(text))
+}
+
+// textRenderer renders blocks as text separated by blank lines, using its functions for the formatting
+type textRenderer struct {
+	escape  func(text string) string
+	inline  func(span inline, content string) string
+	heading func(content string) string
+	code    func(code string) string
+	quote   func(content string) string
+}
+
+func (r textRenderer) render(blocks []block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.kind {
+		case paragraphBlock:
+			parts = append(parts, r.renderInlines(b.inlines))
+		case headingBlock:
+			parts = append(parts, r.heading(r.renderInlines(b.inlines)))
+		case codeBlock:
+			parts = append(parts, r.code(b.code))
+		case quoteBlock:
+			parts = append(parts, r.quote(r.render(b.children)))
+		case listBlock:
+			lines := make([]string, len(b.items))
+			for i, item := range b.items {
+				bullet := "•"
+				if item.ordered {
+					bullet = fmt.Sprintf("%d.", item.number)
+				}
+				lines[i] = strings.Repeat("  ", item.depth) + bullet + " " + r.renderInlines(item.inlines)
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+		case ruleBlock:
+			parts = append(parts, "──────────")
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (r textRenderer) renderInlines(inlines []inline) string {
+	sb := strings.Builder{}
+	for _, span := range inlines {
+		if span.kind == textInline {
+			sb.WriteString(r.escape(span.text))
+		} else {
+			sb.WriteString(r.inline(span, r.renderInlines(span.children)))
+		}
+	}
+	return sb.String()
+}
+
+// plainLink returns the link text followed by the URL, or just the URL if it is the same as the text
+func plainLink(span inline, content string) string {
+	if content == "" || content == span.url || "mailto:"+content == span.url {
+		return span.url
+	}
+	return content + " (" + span.url + ")"
+}
+
+func wrapTag(tag string, content string) string {
+	if tag == "" {
+		return content
+	}
+	return "<" + tag + ">" + content + "</" + tag + ">"
+}
+
+func prefixLines(text string, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+// escapeHTML escapes the characters that are not allowed in text by services with limited HTML support, which are
+// the same as the control characters of the Slack mrkdwn format
+func escapeHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
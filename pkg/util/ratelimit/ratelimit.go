@@ -0,0 +1,162 @@
+// Package ratelimit implements the token bucket limiter used for throttling the sends to a service
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is the maximum number of sends allowed during a period of time
+type Limit struct {
+	Count int
+	Per   time.Duration
+}
+
+// Unlimited is the zero Limit, which does not limit the sends at all
+var Unlimited = Limit{}
+
+// IsUnlimited returns whether the limit does not restrict the sends
+func (l Limit) IsUnlimited() bool {
+	return l.Count <= 0 || l.Per <= 0
+}
+
+// ParseLimit parses a limit in the format "count/period", where period is either one of the units s, m or h, or a
+// duration such as 10s. The values "0" and "none" return Unlimited.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" || strings.EqualFold(s, "none") {
+		return Unlimited, nil
+	}
+
+	countPart, periodPart, found := strings.Cut(s, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected count/period", s)
+	}
+
+	count, err := strconv.Atoi(countPart)
+	if err != nil || count < 1 {
+		return Limit{}, fmt.Errorf("invalid rate limit count %q", countPart)
+	}
+
+	var period time.Duration
+	switch periodPart {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		period, err = time.ParseDuration(periodPart)
+		if err != nil || period <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit period %q", periodPart)
+		}
+	}
+
+	return Limit{Count: count, Per: period}, nil
+}
+
+func (l Limit) String() string {
+	if l.IsUnlimited() {
+		return "none"
+	}
+
+	switch l.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", l.Count)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", l.Count)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", l.Count)
+	}
+	return fmt.Sprintf("%d/%v", l.Count, l.Per)
+}
+
+// Limiter is a token bucket that allows bursts of up to Count sends, refilling at a rate of Count per period
+type Limiter struct {
+	limit  Limit
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewLimiter returns a limiter with a full bucket for the specified limit
+func NewLimiter(limit Limit) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		tokens: float64(limit.Count),
+		now:    time.Now,
+	}
+}
+
+// Limit returns the limit enforced by the limiter
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Wait blocks until a send is allowed by the limit, or ctx is done. Waiting callers are let through in the order
+// they called Wait. A nil limiter never blocks.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.limit.IsUnlimited() {
+		return nil
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket, returning how long the caller needs to wait for it to become available
+func (l *Limiter) reserve() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.limit.Per) / float64(l.limit.Count))
+}
+
+// release returns a token that was reserved, but not used, to the bucket
+func (l *Limiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill()
+	l.tokens = minFloat(l.tokens+1, float64(l.limit.Count))
+}
+
+func (l *Limiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		elapsed := now.Sub(l.last)
+		l.tokens = minFloat(l.tokens+float64(elapsed)*float64(l.limit.Count)/float64(l.limit.Per), float64(l.limit.Count))
+	}
+	l.last = now
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
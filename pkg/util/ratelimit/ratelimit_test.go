@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRateLimit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rate Limit Suite")
+}
+
+var _ = Describe("the rate limit", func() {
+	When("parsing a limit", func() {
+		It("should parse the unit periods", func() {
+			Expect(ParseLimit("20/m")).To(Equal(Limit{Count: 20, Per: time.Minute}))
+			Expect(ParseLimit("1/s")).To(Equal(Limit{Count: 1, Per: time.Second}))
+			Expect(ParseLimit("100/h")).To(Equal(Limit{Count: 100, Per: time.Hour}))
+		})
+
+		It("should parse duration periods", func() {
+			Expect(ParseLimit("5/10s")).To(Equal(Limit{Count: 5, Per: 10 * time.Second}))
+		})
+
+		It("should parse disabled limits", func() {
+			Expect(ParseLimit("none")).To(Equal(Unlimited))
+			Expect(ParseLimit("0")).To(Equal(Unlimited))
+		})
+
+		It("should return an error for invalid limits", func() {
+			for _, s := range []string{"", "20", "x/m", "0/m", "-1/s", "1/x", "1/-5s"} {
+				_, err := ParseLimit(s)
+				Expect(err).To(HaveOccurred(), s)
+			}
+		})
+
+		It("should format limits in the same format", func() {
+			for _, s := range []string{"20/m", "1/s", "3/h", "5/10s", "none"} {
+				limit, err := ParseLimit(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(limit.String()).To(Equal(s))
+			}
+		})
+	})
+
+	When("limiting sends", func() {
+		var limiter *Limiter
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			limiter = NewLimiter(Limit{Count: 2, Per: time.Second})
+			limiter.now = func() time.Time { return now }
+		})
+
+		It("should allow a burst of sends up to the count", func() {
+			Expect(limiter.reserve()).To(BeZero())
+			Expect(limiter.reserve()).To(BeZero())
+		})
+
+		It("should queue the sends that exceed the limit", func() {
+			limiter.reserve()
+			limiter.reserve()
+			Expect(limiter.reserve()).To(Equal(500 * time.Millisecond))
+			Expect(limiter.reserve()).To(Equal(time.Second))
+		})
+
+		It("should refill the bucket over time", func() {
+			limiter.reserve()
+			limiter.reserve()
+			now = now.Add(500 * time.Millisecond)
+			Expect(limiter.reserve()).To(BeZero())
+			Expect(limiter.reserve()).To(Equal(500 * time.Millisecond))
+		})
+
+		It("should not refill the bucket above the count", func() {
+			now = now.Add(time.Hour)
+			limiter.reserve()
+			limiter.reserve()
+			Expect(limiter.reserve()).To(Equal(500 * time.Millisecond))
+		})
+
+		It("should release the reservation when the context is done", func() {
+			limiter.reserve()
+			limiter.reserve()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(limiter.Wait(ctx)).To(MatchError(context.Canceled))
+			Expect(limiter.reserve()).To(Equal(500 * time.Millisecond))
+		})
+
+		It("should never block when unlimited or nil", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(NewLimiter(Unlimited).Wait(ctx)).To(Succeed())
+			Expect((*Limiter)(nil).Wait(ctx)).To(Succeed())
+		})
+	})
+})
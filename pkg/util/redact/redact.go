@@ -0,0 +1,127 @@
+// Package redact implements the masking of secret values in strings, errors, log output and URLs
+package redact
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// Placeholder is used in place of secret values. It only contains unreserved characters, so that it is kept as-is
+// in every part of an URL.
+const Placeholder = "REDACTED"
+
+// Redactor replaces secret values, and their URL encoded forms, with the Placeholder
+type Redactor struct {
+	secrets  []string
+	replacer *strings.Replacer
+}
+
+// New returns a Redactor for the specified secret values. Empty values are ignored.
+func New(secrets ...string) Redactor {
+	return Redactor{}.With(secrets...)
+}
+
+// With returns a Redactor that replaces the additional secret values as well
+func (r Redactor) With(secrets ...string) Redactor {
+	all := append([]string{}, r.secrets...)
+	for _, secret := range secrets {
+		if secret != "" && secret != Placeholder {
+			all = append(all, secret)
+		}
+	}
+
+	if len(all) < 1 {
+		return Redactor{}
+	}
+
+	// Replace longer secrets first, in case one secret contains another
+	sorted := append([]string{}, all...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	pairs := make([]string, 0, len(sorted)*6)
+	for _, secret := range sorted {
+		for _, form := range []string{secret, url.PathEscape(secret), url.QueryEscape(secret)} {
+			pairs = append(pairs, form, Placeholder)
+		}
+	}
+	return Redactor{secrets: all, replacer: strings.NewReplacer(pairs...)}
+}
+
+// String returns s with all the secret values replaced
+func (r Redactor) String(s string) string {
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// URL returns the URL with the userinfo password and all the secret values replaced
+func (r Redactor) URL(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			parsed.User = url.UserPassword(parsed.User.Username(), Placeholder)
+			rawURL = parsed.String()
+		}
+	}
+	return r.String(rawURL)
+}
+
+// Error returns the error with any secrets redacted from its message
+func (r Redactor) Error(err error) error {
+	if err == nil || r.replacer == nil {
+		return err
+	}
+	return redactedError{err, r}
+}
+
+// Add updates the Redactor to also replace the additional secret values
+func (r *Redactor) Add(secrets ...string) {
+	*r = r.With(secrets...)
+}
+
+// Logger returns a logger that redacts the secrets before writing to the specified logger. Secrets that are added to
+// the Redactor after the logger has been created are redacted as well.
+func (r *Redactor) Logger(logger t.StdLogger) t.StdLogger {
+	if logger == nil {
+		return nil
+	}
+	return redactingLogger{logger, r}
+}
+
+// redactedError wraps an error, redacting any secrets from its message
+type redactedError struct {
+	err      error
+	redactor Redactor
+}
+
+func (re redactedError) Error() string {
+	return re.redactor.String(re.err.Error())
+}
+
+func (re redactedError) Unwrap() error {
+	return re.err
+}
+
+// redactingLogger is a StdLogger that redacts secrets from everything that is written to the underlying logger
+type redactingLogger struct {
+	logger   t.StdLogger
+	redactor *Redactor
+}
+
+func (rl redactingLogger) Print(v ...interface{}) {
+	rl.logger.Print(rl.redactor.String(fmt.Sprint(v...)))
+}
+
+func (rl redactingLogger) Printf(format string, v ...interface{}) {
+	rl.logger.Print(rl.redactor.String(fmt.Sprintf(format, v...)))
+}
+
+func (rl redactingLogger) Println(v ...interface{}) {
+	rl.logger.Print(rl.redactor.String(fmt.Sprintln(v...)))
+}
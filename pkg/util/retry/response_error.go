@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ResponseError wraps an error caused by an unsuccessful HTTP response, keeping the status code and the delay from
+// the Retry-After header, if any
+type ResponseError struct {
+	StatusCode int
+	Delay      time.Duration
+	Err        error
+}
+
+// NewResponseError creates a ResponseError from the response, wrapping err
+func NewResponseError(res *http.Response, err error) *ResponseError {
+	return &ResponseError{
+		StatusCode: res.StatusCode,
+		Delay:      ParseRetryAfter(res.Header.Get("Retry-After")),
+		Err:        err,
+	}
+}
+
+func (e *ResponseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus returns the status code of the response
+func (e *ResponseError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// RetryAfter returns the delay requested by the server, or zero if none was requested
+func (e *ResponseError) RetryAfter() time.Duration {
+	return e.Delay
+}
+
+// WithStatusOf returns err wrapped in a ResponseError with the status code and delay of source. This is used when an
+// API error has been parsed from the response body of source, to avoid losing the response details.
+// If source does not carry a status code, err is returned as-is.
+func WithStatusOf(err error, source error) error {
+	var statusErr StatusError
+	if !errors.As(source, &statusErr) {
+		return err
+	}
+
+	resErr := &ResponseError{
+		StatusCode: statusErr.HTTPStatus(),
+		Err:        err,
+	}
+
+	var raErr RetryAfterError
+	if errors.As(source, &raErr) {
+		resErr.Delay = raErr.RetryAfter()
+	}
+
+	return resErr
+}
@@ -0,0 +1,194 @@
+// Package retry implements the retry policy used for re-sending notifications that failed due to transient errors
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultBackoff is the delay used before the first retry if no backoff has been specified
+	DefaultBackoff = time.Second
+	// DefaultMaxBackoff is the upper limit of the exponential backoff if no limit has been specified
+	DefaultMaxBackoff = time.Minute
+)
+
+// Policy describes how many times, and how often, a failed send should be retried
+type Policy struct {
+	// Retries is the maximum number of retries after the initial attempt
+	Retries int
+	// Backoff is the delay before the first retry, which is then doubled for every subsequent retry
+	Backoff time.Duration
+	// MaxBackoff is the upper limit of the exponential backoff. It does not limit delays requested by the server.
+	MaxBackoff time.Duration
+}
+
+// Delay returns the backoff delay before the specified retry (starting at 1)
+func (p Policy) Delay(retry int) time.Duration {
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = DefaultBackoff
+	}
+	maxDelay := p.MaxBackoff
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxBackoff
+	}
+
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// Do calls send until it succeeds, returns a permanent error, the retries are exhausted or ctx is done.
+// If the policy allows any retries, the returned error will be an *Error containing the number of attempts made.
+func (p Policy) Do(ctx context.Context, send func(ctx context.Context) error) error {
+	attempts := 0
+	for {
+		attempts++
+		err := send(ctx)
+		if err == nil {
+			return nil
+		}
+
+		transient, retryAfter := IsTransient(err)
+		if !transient || attempts > p.Retries || ctx.Err() != nil {
+			return p.wrap(err, attempts)
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = p.Delay(attempts)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return p.wrap(err, attempts)
+		}
+	}
+}
+
+func (p Policy) wrap(err error, attempts int) error {
+	if p.Retries < 1 {
+		return err
+	}
+	return &Error{Attempts: attempts, Err: err}
+}
+
+// Error is returned by Policy.Do when all attempts failed, and contains the number of attempts that were made
+type Error struct {
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v (after %d attempt(s))", e.Err, e.Attempts)
+}
+
+// Unwrap returns the error from the last attempt
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned by services that send the message to several targets, e.g. chats or rooms, when some of
+// them have already received it. It is never considered transient, since retrying would send the message to those
+// targets again.
+type PartialError struct {
+	Err error
+}
+
+// Partial wraps err in a PartialError, or returns nil if err is nil
+func Partial(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PartialError{Err: err}
+}
+
+func (e *PartialError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the target that failed
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is implemented by errors that were caused by an unsuccessful HTTP response
+type StatusError interface {
+	error
+	HTTPStatus() int
+}
+
+// RetryAfterError is implemented by errors that carries a delay requested by the server before retrying
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// IsTransient returns whether the error is likely to be resolved by retrying, together with any delay that was
+// requested by the server. Network errors and HTTP 429/5xx responses are considered transient, unless they are wrapped
+// in a PartialError.
+func IsTransient(err error) (transient bool, retryAfter time.Duration) {
+	var partialErr *PartialError
+	if err == nil || errors.Is(err, context.Canceled) || errors.As(err, &partialErr) {
+		return false, 0
+	}
+
+	var raErr RetryAfterError
+	if errors.As(err, &raErr) {
+		retryAfter = raErr.RetryAfter()
+	}
+
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		status := statusErr.HTTPStatus()
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError, retryAfter
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true, retryAfter
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true, retryAfter
+	}
+
+	return false, retryAfter
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either in seconds or a HTTP date
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,149 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/util/retry"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetry(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Retry Suite")
+}
+
+func responseError(status int, retryAfter string) error {
+	res := &http.Response{StatusCode: status, Header: http.Header{}}
+	if retryAfter != "" {
+		res.Header.Set("Retry-After", retryAfter)
+	}
+	return retry.NewResponseError(res, errors.New(http.StatusText(status)))
+}
+
+var _ = Describe("the retry policy", func() {
+	policy := retry.Policy{Retries: 2, Backoff: time.Millisecond}
+
+	When("the send fails with a transient error", func() {
+		It("should retry until the retries are exhausted", func() {
+			attempts := 0
+			err := policy.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				return responseError(http.StatusServiceUnavailable, "")
+			})
+			Expect(attempts).To(Equal(3))
+			Expect(err).To(MatchError("Service Unavailable (after 3 attempt(s))"))
+
+			var retryErr *retry.Error
+			Expect(errors.As(err, &retryErr)).To(BeTrue())
+			Expect(retryErr.Attempts).To(Equal(3))
+		})
+		It("should stop retrying once it succeeds", func() {
+			attempts := 0
+			err := policy.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts < 2 {
+					return responseError(http.StatusTooManyRequests, "0")
+				}
+				return nil
+			})
+			Expect(attempts).To(Equal(2))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the send fails with a permanent error", func() {
+		It("should not retry", func() {
+			attempts := 0
+			err := policy.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				return responseError(http.StatusBadRequest, "")
+			})
+			Expect(attempts).To(Equal(1))
+			Expect(err).To(MatchError("Bad Request (after 1 attempt(s))"))
+		})
+	})
+
+	When("no retries are allowed", func() {
+		It("should return the error as-is", func() {
+			sendErr := responseError(http.StatusBadGateway, "")
+			err := retry.Policy{}.Do(context.Background(), func(ctx context.Context) error {
+				return sendErr
+			})
+			Expect(err).To(Equal(sendErr))
+		})
+	})
+
+	When("the context is done while waiting", func() {
+		It("should return without waiting for the backoff", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			slowPolicy := retry.Policy{Retries: 5, Backoff: time.Hour}
+			err := slowPolicy.Do(ctx, func(ctx context.Context) error {
+				return responseError(http.StatusInternalServerError, "")
+			})
+			Expect(err).To(MatchError(ContainSubstring("after 1 attempt(s)")))
+		})
+	})
+
+	Describe("the backoff delay", func() {
+		It("should double for every retry", func() {
+			p := retry.Policy{Backoff: time.Second}
+			Expect(p.Delay(1)).To(Equal(time.Second))
+			Expect(p.Delay(2)).To(Equal(2 * time.Second))
+			Expect(p.Delay(3)).To(Equal(4 * time.Second))
+		})
+		It("should not exceed the max backoff", func() {
+			p := retry.Policy{Backoff: time.Second, MaxBackoff: 3 * time.Second}
+			Expect(p.Delay(10)).To(Equal(3 * time.Second))
+		})
+	})
+})
+
+var _ = Describe("transient error detection", func() {
+	It("should treat 429 and 5xx responses as transient", func() {
+		Expect(retry.IsTransient(responseError(http.StatusTooManyRequests, ""))).To(BeTrue())
+		Expect(retry.IsTransient(responseError(http.StatusGatewayTimeout, ""))).To(BeTrue())
+	})
+	It("should not treat 4xx responses as transient", func() {
+		Expect(retry.IsTransient(responseError(http.StatusUnauthorized, ""))).To(BeFalse())
+	})
+	It("should not treat errors from sends that reached some of the targets as transient", func() {
+		err := retry.Partial(responseError(http.StatusInternalServerError, "1"))
+		Expect(retry.IsTransient(err)).To(BeFalse())
+		Expect(err).To(MatchError(http.StatusText(http.StatusInternalServerError)))
+		Expect(retry.Partial(nil)).To(Succeed())
+	})
+	It("should not treat cancellation as transient", func() {
+		Expect(retry.IsTransient(context.Canceled)).To(BeFalse())
+	})
+	It("should return the delay requested by the server", func() {
+		_, delay := retry.IsTransient(responseError(http.StatusTooManyRequests, "7"))
+		Expect(delay).To(Equal(7 * time.Second))
+	})
+	It("should keep the status of the source error", func() {
+		err := retry.WithStatusOf(errors.New("api error"), responseError(http.StatusTooManyRequests, "2"))
+		transient, delay := retry.IsTransient(err)
+		Expect(transient).To(BeTrue())
+		Expect(delay).To(Equal(2 * time.Second))
+		Expect(err).To(MatchError("api error"))
+	})
+})
+
+var _ = Describe("parsing Retry-After", func() {
+	It("should parse delays in seconds", func() {
+		Expect(retry.ParseRetryAfter("120")).To(Equal(2 * time.Minute))
+	})
+	It("should parse HTTP dates", func() {
+		date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+		Expect(retry.ParseRetryAfter(date)).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+	It("should return zero for invalid values", func() {
+		Expect(retry.ParseRetryAfter("soon")).To(BeZero())
+	})
+})
@@ -0,0 +1,218 @@
+// Package templates provides the helper functions available in message templates, modelled after the ones in sprig
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// New returns a new template with the helper functions added
+func New(name string) *template.Template {
+	return template.New(name).Funcs(Funcs())
+}
+
+// Parse parses the body as a template with the helper functions added
+func Parse(name string, body string) (*template.Template, error) {
+	return New(name).Parse(body)
+}
+
+// ParseFile parses the file as a template with the helper functions added, named after the file
+func ParseFile(path string) (*template.Template, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, string(body))
+}
+
+// Funcs returns the helper functions. As in sprig, the value that is operated on is the last argument, allowing the
+// functions to be used in pipelines, e.g. {{ .Message | trunc 100 | upper }}.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		// Strings
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"trunc":      trunc,
+		"abbrev":     abbrev,
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"quote":      func(s interface{}) string { return fmt.Sprintf("%q", toString(s)) },
+		"squote":     func(s interface{}) string { return "'" + toString(s) + "'" },
+		"join":       join,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+
+		// Defaults
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		// Dates
+		"now":  time.Now,
+		"date": date,
+
+		// Encoding
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+
+		// Lists
+		"list": func(values ...interface{}) []interface{} { return values },
+	}
+}
+
+// title returns the string with the first letter of every word in upper case
+func title(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
+
+// trunc returns the first length characters of the string, or the last if length is negative
+func trunc(length int, s string) string {
+	runes := []rune(s)
+	if length < 0 && -length < len(runes) {
+		return string(runes[len(runes)+length:])
+	}
+	if length >= 0 && length < len(runes) {
+		return string(runes[:length])
+	}
+	return s
+}
+
+// abbrev truncates the string to width characters, including an ellipsis if it was shortened
+func abbrev(width int, s string) string {
+	runes := []rune(s)
+	if width < 4 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// indent adds the number of spaces to the start of every line in the string
+func indent(spaces int, s string) string {
+	padding := strings.Repeat(" ", spaces)
+	return padding + strings.ReplaceAll(s, "\n", "\n"+padding)
+}
+
+// join returns the string representation of the values in the list, joined using the separator
+func join(sep string, list interface{}) string {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return toString(list)
+	}
+
+	values := make([]string, value.Len())
+	for i := range values {
+		values[i] = toString(value.Index(i).Interface())
+	}
+	return strings.Join(values, sep)
+}
+
+// defaultValue returns the given value, unless it is empty, in which case def is returned
+func defaultValue(def interface{}, given ...interface{}) interface{} {
+	if len(given) < 1 || empty(given[0]) {
+		return def
+	}
+	return given[0]
+}
+
+// empty returns whether the value is nil or the zero value of its type, or an empty collection
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// coalesce returns the first value that is not empty
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !empty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+// ternary returns whenTrue if the condition is true, and whenFalse otherwise
+func ternary(whenTrue interface{}, whenFalse interface{}, condition bool) interface{} {
+	if condition {
+		return whenTrue
+	}
+	return whenFalse
+}
+
+// date formats the time using the Go reference time layout, e.g. "2006-01-02 15:04"
+func date(layout string, value interface{}) string {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Format(layout)
+	case *time.Time:
+		return t.Format(layout)
+	case int64:
+		return time.Unix(t, 0).Format(layout)
+	case int:
+		return time.Unix(int64(t), 0).Format(layout)
+	default:
+		return time.Now().Format(layout)
+	}
+}
+
+// toJSON returns the JSON encoding of the value, or an empty string if it cannot be encoded
+func toJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// toPrettyJSON returns the indented JSON encoding of the value, or an empty string if it cannot be encoded
+func toPrettyJSON(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
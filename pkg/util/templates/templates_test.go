@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTemplates(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Templates Suite")
+}
+
+func render(body string, data interface{}) string {
+	tpl, err := Parse("test", body)
+	Expect(err).NotTo(HaveOccurred())
+	sb := strings.Builder{}
+	Expect(tpl.Execute(&sb, data)).To(Succeed())
+	return sb.String()
+}
+
+var _ = Describe("the template helpers", func() {
+	It("should transform strings in pipelines", func() {
+		Expect(render(`{{ . | trim | upper }}`, " message ")).To(Equal("MESSAGE"))
+		Expect(render(`{{ . | title }}`, "disk almost full")).To(Equal("Disk Almost Full"))
+		Expect(render(`{{ . | replace "-" " " }}`, "a-b-c")).To(Equal("a b c"))
+		Expect(render(`{{ . | trimPrefix "[" | trimSuffix "]" }}`, "[tag]")).To(Equal("tag"))
+		Expect(render(`{{ if . | hasPrefix "db" }}yes{{ end }}`, "db01")).To(Equal("yes"))
+		Expect(render(`{{ . | quote }} {{ . | squote }}`, "a")).To(Equal(`"a" 'a'`))
+		Expect(render(`{{ "ab" | repeat 2 }}`, nil)).To(Equal("abab"))
+	})
+
+	It("should shorten strings", func() {
+		Expect(render(`{{ . | trunc 3 }}`, "åäöü")).To(Equal("åäö"))
+		Expect(render(`{{ . | trunc -2 }}`, "åäöü")).To(Equal("öü"))
+		Expect(render(`{{ . | abbrev 6 }}`, "message")).To(Equal("mes..."))
+		Expect(render(`{{ . | abbrev 10 }}`, "message")).To(Equal("message"))
+	})
+
+	It("should indent every line", func() {
+		Expect(render(`{{ . | indent 2 }}`, "a\nb")).To(Equal("  a\n  b"))
+		Expect(render(`x:{{ . | nindent 2 }}`, "a")).To(Equal("x:\n  a"))
+	})
+
+	It("should join and split lists", func() {
+		Expect(render(`{{ . | join ", " }}`, []int{1, 2, 3})).To(Equal("1, 2, 3"))
+		Expect(render(`{{ range "a,b" | splitList "," }}[{{ . }}]{{ end }}`, nil)).To(Equal("[a][b]"))
+		Expect(render(`{{ list "a" 1 | join "-" }}`, nil)).To(Equal("a-1"))
+	})
+
+	It("should use defaults for empty values", func() {
+		data := map[string]string{"env": "", "host": "web01"}
+		Expect(render(`{{ .env | default "prod" }}`, data)).To(Equal("prod"))
+		Expect(render(`{{ .host | default "unknown" }}`, data)).To(Equal("web01"))
+		Expect(render(`{{ .missing | default "none" }}`, data)).To(Equal("none"))
+		Expect(render(`{{ coalesce .env .host }}`, data)).To(Equal("web01"))
+		Expect(render(`{{ empty .env }}`, data)).To(Equal("true"))
+		Expect(render(`{{ ternary "up" "down" true }}`, nil)).To(Equal("up"))
+	})
+
+	It("should format dates", func() {
+		timestamp := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+		Expect(render(`{{ . | date "2006-01-02 15:04" }}`, timestamp)).To(Equal("2023-04-05 06:07"))
+		Expect(render(`{{ now | date "2006" }}`, nil)).To(Equal(time.Now().Format("2006")))
+	})
+
+	It("should encode values as JSON", func() {
+		Expect(render(`{{ toJson . }}`, map[string]string{"key": "value"})).To(Equal(`{"key":"value"}`))
+		Expect(render(`{{ toPrettyJson . }}`, []int{1})).To(Equal("[\n  1\n]"))
+	})
+
+	It("should parse template files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "message.tmpl")
+		Expect(os.WriteFile(path, []byte(`{{ . | upper }}`), 0600)).To(Succeed())
+		tpl, err := ParseFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		sb := strings.Builder{}
+		Expect(tpl.Execute(&sb, "file")).To(Succeed())
+		Expect(sb.String()).To(Equal("FILE"))
+
+		_, err = ParseFile(filepath.Join(GinkgoT().TempDir(), "missing.tmpl"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,9 @@
 package shoutrrr
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/containrrr/shoutrrr/internal/meta"
 	"github.com/containrrr/shoutrrr/pkg/router"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -13,14 +16,20 @@ func SetLogger(logger types.StdLogger) {
 	defaultRouter.SetLogger(logger)
 }
 
+// SetHTTPClient sets the client that the services will use for their HTTP requests
+func SetHTTPClient(client *http.Client) {
+	defaultRouter.SetHTTPClient(client)
+}
+
 // Send notifications using a supplied url and message
 func Send(rawURL string, message string) error {
-	service, err := defaultRouter.Locate(rawURL)
-	if err != nil {
-		return err
-	}
+	return defaultRouter.Route(rawURL, message)
+}
 
-	return service.Send(message, &types.Params{})
+// SendContext sends notifications using a supplied url and message, aborting the send when ctx is done.
+// The router options in the URL, such as retries, ratelimit and format, are applied to the send.
+func SendContext(ctx context.Context, rawURL string, message string) error {
+	return defaultRouter.RouteContext(ctx, rawURL, message)
 }
 
 // CreateSender returns a notification sender configured according to the supplied URL
@@ -12,10 +12,9 @@ import (
 	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
 )
 
-var (
-	serviceRouter router.ServiceRouter
-	services      = serviceRouter.ListServices()
-)
+// serviceRouter is only used for listing and creating services, which are looked up when the command is run, so that
+// services registered after this package has been initialized are included as well
+var serviceRouter router.ServiceRouter
 
 // Cmd prints documentation for services
 var Cmd = &cobra.Command{
@@ -23,11 +22,13 @@ var Cmd = &cobra.Command{
 	Short: "Print documentation for services",
 	Run:   Run,
 	Args: func(cmd *cobra.Command, args []string) error {
-		serviceList := strings.Join(services, ", ")
+		serviceList := strings.Join(serviceRouter.ListServices(), ", ")
 		cmd.SetUsageTemplate(cmd.UsageTemplate() + "\nAvailable services: \n  " + serviceList + "\n")
 		return cobra.MinimumNArgs(1)(cmd, args)
 	},
-	ValidArgs: services,
+	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return serviceRouter.ListServices(), cobra.ShellCompDirectiveNoFileComp
+	},
 }
 
 func init() {
@@ -66,10 +67,43 @@ func printDocs(format string, services []string) cli.Result {
 		if err != nil {
 			return cli.InvalidUsage("failed to init service: " + err.Error())
 		}
+		if docs, found := router.GetServiceDocs(scheme); found {
+			printServiceDocs(docs)
+		}
 		config := f.GetServiceConfig(service)
+		if config == nil {
+			fmt.Printf("The %v service does not expose its config\n\n", scheme)
+			continue
+		}
 		configNode := f.GetConfigFormat(config)
 		fmt.Println(renderer.RenderTree(configNode, scheme))
+		printParamKeys(format, f.GetParamKeys(config))
 	}
 
 	return cli.Success
 }
+
+// printServiceDocs prints the documentation metadata of a registered service
+func printServiceDocs(docs router.ServiceDocs) {
+	if docs.Description != "" {
+		fmt.Println(docs.Description)
+	}
+	if docs.Link != "" {
+		fmt.Println("See " + docs.Link)
+	}
+	if docs.Description != "" || docs.Link != "" {
+		fmt.Println()
+	}
+}
+
+// printParamKeys prints the keys of the props that can be overridden per send using params
+func printParamKeys(format string, keys []string) {
+	if len(keys) < 1 {
+		return
+	}
+	if format == "markdown" {
+		fmt.Printf("Params that can be overridden per send: `%s`\n\n", strings.Join(keys, "`, `"))
+		return
+	}
+	fmt.Printf("Params that can be overridden per send: %s\n\n", strings.Join(keys, ", "))
+}
@@ -91,7 +91,10 @@ func Run(cmd *cobra.Command, _ []string) {
 
 	if !generatorFlag.Changed {
 		// try to use the service default generator if one exists
-		generator, _ = generators.NewGenerator(serviceSchema)
+		var found bool
+		if generator, found = router.NewServiceGenerator(serviceSchema); !found {
+			generator, _ = generators.NewGenerator(serviceSchema)
+		}
 	}
 
 	if generator != nil {
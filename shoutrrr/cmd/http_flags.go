@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/spf13/pflag"
+
+	"github.com/containrrr/shoutrrr/pkg/util/httpclient"
+)
+
+// AddHTTPFlags adds the flags used for configuring the HTTP client of the services
+func AddHTTPFlags(flags *pflag.FlagSet) {
+	flags.String("proxy", "", "The URL of the proxy used for HTTP requests")
+	flags.String("ca-file", "", "A PEM file with additional CA certificates to trust for HTTPS requests")
+	flags.String("client-cert", "", "A PEM file with the client certificate used for mutual TLS")
+	flags.String("client-key", "", "A PEM file with the private key of the client certificate")
+	flags.Bool("insecure", false, "Skip the verification of server certificates for HTTPS requests")
+}
+
+// HTTPClient returns the HTTP client configured by the flags added using AddHTTPFlags, or nil if none of them were set
+func HTTPClient(flags *pflag.FlagSet) (*http.Client, error) {
+	options := httpclient.Options{}
+	options.ProxyURL, _ = flags.GetString("proxy")
+	options.CAFile, _ = flags.GetString("ca-file")
+	options.CertFile, _ = flags.GetString("client-cert")
+	options.KeyFile, _ = flags.GetString("client-key")
+	options.InsecureSkipVerify, _ = flags.GetBool("insecure")
+
+	if options == (httpclient.Options{}) {
+		return nil, nil
+	}
+	return httpclient.New(options)
+}
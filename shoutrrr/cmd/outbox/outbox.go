@@ -0,0 +1,205 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/containrrr/shoutrrr/pkg/outbox"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+)
+
+// Cmd is used to inspect and manage the notifications stored in the outbox
+var Cmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect and manage notifications that failed to send",
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the notifications stored in the outbox",
+	Args:  cobra.NoArgs,
+	RunE:  wrapRun(runList),
+}
+
+var retryCmd = &cobra.Command{
+	Use:   "retry [ID]...",
+	Short: "Retry sending the specified notifications, or all of them if no IDs are given",
+	RunE:  wrapRun(runRetry),
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge [ID]...",
+	Short: "Remove the specified notifications from the outbox, or all of them if no IDs are given",
+	RunE:  wrapRun(runPurge),
+}
+
+func init() {
+	Cmd.PersistentFlags().StringP("dir", "d", "", "The outbox directory (default \"$SHOUTRRR_OUTBOX\" or the user cache directory)")
+	retryCmd.Flags().BoolP("verbose", "v", false, "")
+	cli.AddHTTPFlags(retryCmd.Flags())
+
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(retryCmd)
+	Cmd.AddCommand(purgeCmd)
+}
+
+// DefaultDir returns the outbox directory used when none has been specified
+func DefaultDir() string {
+	if dir := viper.GetViper().GetString("SHOUTRRR_OUTBOX"); dir != "" {
+		return dir
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "shoutrrr", "outbox")
+}
+
+func openStore(cmd *cobra.Command) (*outbox.Store, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = DefaultDir()
+	}
+
+	store, err := outbox.NewStore(dir)
+	if err != nil {
+		return nil, cli.ConfigurationError(err.Error())
+	}
+	return store, nil
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	store, err := openStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	records, err := store.List()
+	if err != nil {
+		return cli.TaskUnavailable(err.Error())
+	}
+
+	if len(records) < 1 {
+		fmt.Println("The outbox is empty")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tCREATED\tATTEMPTS\tNEXT ATTEMPT\tURL\tLAST ERROR")
+	for _, record := range records {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			record.ID,
+			record.Created.Format(time.RFC3339),
+			record.Attempts,
+			record.NextAttempt.Format(time.RFC3339),
+			router.RedactURL(record.URL),
+			util.Ellipsis(record.LastError, 60),
+		)
+	}
+	return writer.Flush()
+}
+
+func runRetry(cmd *cobra.Command, args []string) error {
+	store, err := openStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	ids, err := recordIDs(store, args)
+	if err != nil {
+		return err
+	}
+
+	logger := util.DiscardLogger
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
+	}
+
+	sr, err := router.New(logger)
+	if err != nil {
+		return cli.ConfigurationError(err.Error())
+	}
+	client, err := cli.HTTPClient(cmd.Flags())
+	if err != nil {
+		return cli.ConfigurationError(fmt.Sprintf("error invoking retry: %s", err))
+	}
+	sr.SetHTTPClient(client)
+
+	ob := outbox.New(sr, store, logger)
+	failed := 0
+	for _, id := range ids {
+		if err := ob.Replay(context.Background(), id); err != nil {
+			failed++
+			fmt.Printf("%s: %v\n", id, err)
+			continue
+		}
+		fmt.Printf("%s: sent\n", id)
+	}
+
+	if failed > 0 {
+		return cli.TaskUnavailable(fmt.Sprintf("failed to send %d of %d notification(s)", failed, len(ids)))
+	}
+	return nil
+}
+
+func runPurge(cmd *cobra.Command, args []string) error {
+	store, err := openStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	ids, err := recordIDs(store, args)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := store.Remove(id); err != nil {
+			return cli.TaskUnavailable(err.Error())
+		}
+	}
+	fmt.Printf("Removed %d notification(s) from the outbox\n", len(ids))
+	return nil
+}
+
+// recordIDs returns the IDs given as arguments, or the IDs of all records in the store if none were given
+func recordIDs(store *outbox.Store, args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	records, err := store.List()
+	if err != nil {
+		return nil, cli.TaskUnavailable(err.Error())
+	}
+
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids, nil
+}
+
+// wrapRun reports non-usage errors and exits using their exit code, to not invoke the cobra error output
+func wrapRun(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := run(cmd, args)
+		var result cli.Result
+		if errors.As(err, &result) && result.ExitCode != cli.ExUsage {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+		return err
+	}
+}
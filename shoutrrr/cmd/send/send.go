@@ -1,19 +1,25 @@
 package send
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/spf13/cobra"
 
 	"github.com/containrrr/shoutrrr/internal/dedupe"
 	intutil "github.com/containrrr/shoutrrr/internal/util"
+	"github.com/containrrr/shoutrrr/pkg/outbox"
 	"github.com/containrrr/shoutrrr/pkg/router"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/templates"
 	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
 )
 
@@ -30,12 +36,32 @@ func init() {
 	Cmd.Flags().BoolP("verbose", "v", false, "")
 
 	Cmd.Flags().StringArrayP("url", "u", []string{}, "The notification url")
-	_ = Cmd.MarkFlagRequired("url")
+
+	Cmd.Flags().StringP("config", "c", "", "The config file defining the named targets (default \"$SHOUTRRR_CONFIG\")")
+	Cmd.Flags().StringArray("target", []string{}, "The name of a target, or group of targets, defined in the config file")
 
 	Cmd.Flags().StringP("message", "m", "", "The message to send to the notification url, or - to read message from stdin")
 	_ = Cmd.MarkFlagRequired("message")
 
 	Cmd.Flags().StringP("title", "t", "", "The title used for services that support it")
+
+	Cmd.Flags().StringArray("attach", []string{}, "Attach the file at the specified path, can be used multiple times")
+
+	Cmd.Flags().String("template", "", "Render the message using the template file before sending")
+	Cmd.Flags().StringArray("param", []string{}, "A param in the format key=value, passed to the template and services, can be used multiple times")
+	Cmd.Flags().String("params-json", "", "A JSON object of params, which are overridden by any --param flags")
+
+	Cmd.Flags().String("strategy", "broadcast", "The delivery strategy, one of broadcast, failover or quorum:N")
+
+	Cmd.Flags().String("outbox", "", "Store notifications that fail to send in the specified outbox directory")
+
+	Cmd.Flags().Bool("dry-run", false, "Print the requests that the services would make instead of sending the notification")
+
+	Cmd.Flags().Bool("show-secrets", false, "Do not redact secrets from the verbose output")
+
+	Cmd.Flags().StringP("output", "o", "text", "The format of the send results, one of text or json")
+
+	cli.AddHTTPFlags(Cmd.Flags())
 }
 
 func logf(format string, a ...interface{}) {
@@ -45,11 +71,80 @@ func logf(format string, a ...interface{}) {
 func run(cmd *cobra.Command) error {
 	flags := cmd.Flags()
 	verbose, _ := flags.GetBool("verbose")
+	showSecrets, _ := flags.GetBool("show-secrets")
 
 	urls, _ := flags.GetStringArray("url")
 	urls = dedupe.RemoveDuplicates(urls)
+	configFile, _ := flags.GetString("config")
+	targets, _ := flags.GetStringArray("target")
 	message, _ := flags.GetString("message")
 	title, _ := flags.GetString("title")
+	outboxDir, _ := flags.GetString("outbox")
+	dryRun, _ := flags.GetBool("dry-run")
+	attachPaths, _ := flags.GetStringArray("attach")
+	templateFile, _ := flags.GetString("template")
+	paramFlags, _ := flags.GetStringArray("param")
+	paramsJSON, _ := flags.GetString("params-json")
+	output, _ := flags.GetString("output")
+
+	strategyFlag, _ := flags.GetString("strategy")
+	strategy, err := router.ParseStrategy(strategyFlag)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	if configFile == "" {
+		if len(targets) > 0 {
+			return cli.InvalidUsage("a config file is required when using targets")
+		}
+		if len(urls) < 1 {
+			return cli.InvalidUsage("either a url or a config file is required")
+		}
+	}
+
+	if output != "text" && output != "json" {
+		return cli.InvalidUsage(fmt.Sprintf("invalid output format %q, expected text or json", output))
+	}
+
+	if outboxDir != "" && output == "json" {
+		return cli.InvalidUsage("json output cannot be used together with an outbox")
+	}
+
+	if outboxDir != "" && len(attachPaths) > 0 {
+		return cli.InvalidUsage("attachments cannot be used together with an outbox")
+	}
+
+	if outboxDir != "" && templateFile != "" {
+		return cli.InvalidUsage("templates cannot be used together with an outbox")
+	}
+
+	if dryRun && outboxDir != "" {
+		return cli.InvalidUsage("dry runs cannot be used together with an outbox")
+	}
+
+	if dryRun && len(attachPaths) > 0 {
+		return cli.InvalidUsage("attachments cannot be used together with a dry run")
+	}
+
+	params, err := parseParamsJSON(paramsJSON)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+	if err := parseParams(paramFlags, params); err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+	if title != "" {
+		params[types.TitleKey] = title
+	}
+
+	var attachments []types.Attachment
+	for _, path := range attachPaths {
+		attachment, err := types.LoadAttachment(path)
+		if err != nil {
+			return cli.InvalidUsage(fmt.Sprintf("failed to read attachment: %s", err))
+		}
+		attachments = append(attachments, attachment)
+	}
 
 	if message == "-" {
 		logf("Reading from STDIN...")
@@ -62,46 +157,258 @@ func run(cmd *cobra.Command) error {
 		message = sb.String()
 	}
 
-	var logger *log.Logger
+	var tpl *template.Template
+	if templateFile != "" {
+		if tpl, err = templates.ParseFile(templateFile); err != nil {
+			return cli.InvalidUsage(fmt.Sprintf("failed to load template: %s", err))
+		}
+	}
 
+	var logger *log.Logger
 	if verbose {
-		urlsPrefix := "URLs:"
-		for i, url := range urls {
-			logf("%s %s", urlsPrefix, url)
-			if i == 0 {
-				// Only display "URLs:" prefix for first line, replace with indentation for the the subsequent
-				urlsPrefix = strings.Repeat(" ", len(urlsPrefix))
-			}
-		}
-		logf("Message: %s", util.Ellipsis(message, 100))
-		if title != "" {
-			logf("Title: %v", title)
-		}
 		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
 	} else {
 		logger = util.DiscardLogger
 	}
 
-	sr, err := router.New(logger, urls...)
+	sr, err := createRouter(logger, showSecrets, configFile, targets, urls)
 	if err != nil {
 		return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
 	} else {
-		params := make(types.Params)
-		if title != "" {
-			params["title"] = title
+		sr.Strategy = strategy
+		sr.SetTemplate(tpl)
+
+		client, err := cli.HTTPClient(flags)
+		if err != nil {
+			return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
+		} else if client != nil {
+			sr.SetHTTPClient(client)
+		}
+
+		if verbose {
+			if configFile != "" {
+				logf("Config: %s", configFile)
+				if len(targets) > 0 {
+					logf("Targets: %s", strings.Join(targets, ", "))
+				}
+			}
+			urlsPrefix := "URLs:"
+			for i, url := range sr.RedactedServiceURLs() {
+				logf("%s %s", urlsPrefix, url)
+				if i == 0 {
+					// Only display "URLs:" prefix for first line, replace with indentation for the the subsequent
+					urlsPrefix = strings.Repeat(" ", len(urlsPrefix))
+				}
+			}
+			logf("Message: %s", util.Ellipsis(message, 100))
+			if title != "" {
+				logf("Title: %v", title)
+			}
+			if len(params) > 0 {
+				// Only the keys are logged, since the values could be secrets
+				keys := make([]string, 0, len(params))
+				for key := range params {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				logf("Params: %s", strings.Join(keys, ", "))
+			}
+			if templateFile != "" {
+				logf("Template: %s", templateFile)
+			}
+			for _, attachment := range attachments {
+				logf("Attachment: %s (%s, %d byte(s))", attachment.Name, attachment.MediaType(), len(attachment.Content))
+			}
 		}
-		errs := sr.SendAsync(message, &params)
-		for err := range errs {
+
+		var errs []error
+		var results []router.SendResult
+		if outboxDir != "" {
+			store, err := outbox.NewStore(outboxDir)
 			if err != nil {
-				return cli.TaskUnavailable(err.Error())
+				return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
+			}
+			errs = outbox.New(sr, store, logger).Send(message, &params)
+		} else {
+			if dryRun {
+				results = sr.DryRun(message, &params)
+			} else if len(attachments) > 0 {
+				items := []types.MessageItem{{Text: message, Attachments: attachments}}
+				results = sr.SendItemsContextWithResults(context.Background(), items, params)
+			} else {
+				results = sr.SendWithResults(message, &params)
+			}
+			errs = router.ResultErrors(results)
+
+			if output == "json" {
+				if err := writeResults(os.Stdout, results); err != nil {
+					return fmt.Errorf("failed to write results: %w", err)
+				}
+			} else if dryRun {
+				if err := writeRequests(os.Stdout, sr.RedactedServiceURLs(), results); err != nil {
+					return fmt.Errorf("failed to write requests: %w", err)
+				}
+			}
+		}
+
+		delivered := sr.Delivered(errs)
+		if results != nil {
+			delivered = sr.DeliveredResults(results)
+		}
+
+		if !delivered {
+			for _, err := range errs {
+				if err != nil {
+					return cli.TaskUnavailable(err.Error())
+				}
+			}
+		}
+
+		if output == "json" {
+			return nil
+		}
+
+		urls := sr.RedactedServiceURLs()
+		for i, err := range errs {
+			if err != nil {
+				logf("Failed to send notification using %s: %s", urls[i], err)
+			} else if results != nil && results[i].Skipped() {
+				logf("Skipped %s", urls[i])
+			} else if dryRun {
+				logf("Dry run using %s: %d request(s)", urls[i], len(results[i].Requests))
+			} else {
+				logf("Notification sent using %s", urls[i])
 			}
-			logf("Notification sent")
 		}
 	}
 
 	return nil
 }
 
+// parseParams adds the params from the param flags, in the format key=value, to params
+func parseParams(paramFlags []string, params types.Params) error {
+	for _, param := range paramFlags {
+		key, value, found := strings.Cut(param, "=")
+		if !found || key == "" {
+			return fmt.Errorf("invalid param %q, expected key=value", param)
+		}
+		params[key] = value
+	}
+	return nil
+}
+
+// parseParamsJSON returns the params from a JSON object. Numbers and booleans are used as strings, and arrays of them
+// are joined using commas, which is the format used for list props.
+func parseParamsJSON(paramsJSON string) (types.Params, error) {
+	params := types.Params{}
+	if paramsJSON == "" {
+		return params, nil
+	}
+
+	var values map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(paramsJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid params JSON, expected an object: %w", err)
+	}
+
+	for key, value := range values {
+		param, err := paramValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for param %q: %w", key, err)
+		}
+		params[key] = param
+	}
+	return params, nil
+}
+
+// paramValue returns the param string for a decoded JSON value
+func paramValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number, bool:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			if _, isList := item.([]interface{}); isList {
+				return "", fmt.Errorf("nested arrays are not supported")
+			}
+			var err error
+			if items[i], err = paramValue(item); err != nil {
+				return "", err
+			}
+		}
+		return strings.Join(items, ","), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("objects are not supported")
+	}
+}
+
+// writeResults writes the send results as an indented JSON array
+func writeResults(w io.Writer, results []router.SendResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// writeRequests writes the requests of the dry run results in the HTTP message format, each preceded by a comment with
+// the URL of the service
+func writeRequests(w io.Writer, urls []string, results []router.SendResult) error {
+	sb := strings.Builder{}
+	for i, result := range results {
+		for _, request := range result.Requests {
+			fmt.Fprintf(&sb, "# %s\n%s %s\n", urls[i], request.Method, request.URL)
+
+			keys := make([]string, 0, len(request.Header))
+			for key := range request.Header {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				for _, value := range request.Header[key] {
+					fmt.Fprintf(&sb, "%s: %s\n", key, value)
+				}
+			}
+
+			fmt.Fprintf(&sb, "\n%s\n\n", request.Body)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// createRouter creates a router using the targets from the config file, if one is specified, and the URLs
+func createRouter(logger *log.Logger, showSecrets bool, configFile string, targets []string, urls []string) (*router.ServiceRouter, error) {
+	sr, err := router.New(logger)
+	if err != nil {
+		return nil, err
+	}
+	sr.ShowSecrets = showSecrets
+
+	// Only use the default target of the config file if no URLs were given either
+	if configFile != "" && (len(targets) > 0 || len(urls) < 1) {
+		config, err := router.LoadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := sr.AddFromConfig(config, targets...); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, url := range urls {
+		if err := sr.AddService(url); err != nil {
+			return nil, err
+		}
+	}
+	return sr, nil
+}
+
 // Run the send command
 func Run(cmd *cobra.Command, _ []string) error {
 	err := run(cmd)
@@ -0,0 +1,198 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/containrrr/shoutrrr/pkg/receivers/alertmanager"
+	"github.com/containrrr/shoutrrr/pkg/server"
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+)
+
+// Cmd serves a HTTP API for sending notifications
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a HTTP API for sending notifications",
+	Args:  cobra.NoArgs,
+	RunE:  Run,
+}
+
+func init() {
+	Cmd.Flags().BoolP("verbose", "v", false, "")
+	Cmd.Flags().StringP("listen", "l", "localhost:8080", "The address to listen on")
+	Cmd.Flags().StringArrayP("url", "u", []string{}, "The notification url of the default target")
+	Cmd.Flags().StringArray("target", []string{}, "A named target notification url, in the format name=url")
+	Cmd.Flags().String("token", "", "The bearer token required for API requests (default \"$SHOUTRRR_TOKEN\")")
+	Cmd.Flags().String("alertmanager-target", "", "Receive Alertmanager webhooks on /alertmanager, forwarding them to the target")
+	Cmd.Flags().String("alertmanager-template", "", "The template file used for rendering the Alertmanager message")
+}
+
+func logf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+}
+
+func run(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	verbose, _ := flags.GetBool("verbose")
+	listen, _ := flags.GetString("listen")
+	urls, _ := flags.GetStringArray("url")
+	targetFlags, _ := flags.GetStringArray("target")
+
+	token, _ := flags.GetString("token")
+	if token == "" {
+		token = viper.GetViper().GetString("SHOUTRRR_TOKEN")
+	}
+
+	targets, err := parseTargets(urls, targetFlags)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	var logger *log.Logger
+	if verbose {
+		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
+	} else {
+		logger = util.DiscardLogger
+	}
+
+	srv, err := server.New(logger, token, targets)
+	if err != nil {
+		return cli.ConfigurationError(fmt.Sprintf("error invoking serve: %s", err))
+	}
+
+	if err := verifyTargets(srv, targets); err != nil {
+		return cli.ConfigurationError(fmt.Sprintf("error invoking serve: %s", err))
+	}
+
+	if err := addAlertmanagerReceiver(cmd, srv); err != nil {
+		return err
+	}
+
+	if token == "" {
+		logf("Warning: no token has been set, the API will not require authorization")
+	}
+
+	return listenAndServe(listen, srv)
+}
+
+// verifyTargets checks the credentials of the services of the targets that support it, so that invalid credentials are
+// reported when starting the server instead of when the first message is sent
+func verifyTargets(srv *server.Server, targets map[string][]string) error {
+	for name := range targets {
+		sr, _ := srv.Target(name)
+		for _, err := range sr.Verify(context.Background()) {
+			if err != nil {
+				return fmt.Errorf("target %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addAlertmanagerReceiver adds the Alertmanager webhook receiver to the server, if it has been enabled
+func addAlertmanagerReceiver(cmd *cobra.Command, srv *server.Server) error {
+	target, _ := cmd.Flags().GetString("alertmanager-target")
+	templateFile, _ := cmd.Flags().GetString("alertmanager-template")
+	if target == "" {
+		if templateFile != "" {
+			return cli.InvalidUsage("--alertmanager-template requires --alertmanager-target")
+		}
+		return nil
+	}
+
+	sr, found := srv.Target(target)
+	if !found {
+		return cli.InvalidUsage(fmt.Sprintf("unknown alertmanager target %q", target))
+	}
+
+	templater := &standard.Templater{}
+	if templateFile != "" {
+		if err := templater.SetTemplateFile(alertmanager.MessageTemplate, templateFile); err != nil {
+			return cli.ConfigurationError(fmt.Sprintf("error loading alertmanager template: %s", err))
+		}
+	}
+
+	srv.Handle("/alertmanager", alertmanager.NewReceiver(sr, templater))
+	logf("Receiving Alertmanager webhooks on /alertmanager for target %q", target)
+	return nil
+}
+
+// listenAndServe serves the handler on the address until the process receives an interrupt or termination signal
+func listenAndServe(address string, handler http.Handler) error {
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		logf("Listening on %v", address)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return cli.TaskUnavailable(fmt.Sprintf("error serving API: %s", err))
+	case <-ctx.Done():
+	}
+
+	logf("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return cli.TaskUnavailable(fmt.Sprintf("error shutting down: %s", err))
+	}
+	return nil
+}
+
+// parseTargets returns the service URLs for each target, using the URLs as the default target
+func parseTargets(urls []string, targetFlags []string) (map[string][]string, error) {
+	targets := map[string][]string{}
+	if len(urls) > 0 {
+		targets[server.DefaultTarget] = urls
+	}
+
+	for _, target := range targetFlags {
+		name, targetURL, found := strings.Cut(target, "=")
+		if !found || name == "" || targetURL == "" {
+			return nil, fmt.Errorf("invalid target %q, expected name=url", target)
+		}
+		targets[name] = append(targets[name], targetURL)
+	}
+
+	if len(targets) < 1 {
+		return nil, errors.New("at least one url or target is required")
+	}
+
+	return targets, nil
+}
+
+// Run the serve command
+func Run(cmd *cobra.Command, _ []string) error {
+	err := run(cmd)
+	if err != nil {
+		if result, ok := err.(cli.Result); ok && result.ExitCode != cli.ExUsage {
+			// If the error is not related to the CLI usage, report error and exit to not invoke cobra error output
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+	}
+	return err
+}
@@ -1,12 +1,15 @@
 package verify
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/internal/util"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/fatih/color"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -25,11 +28,14 @@ var sr router.ServiceRouter
 func init() {
 	Cmd.Flags().StringP("url", "u", "", "The notification url")
 	_ = Cmd.MarkFlagRequired("url")
+
+	Cmd.Flags().Bool("show-secrets", false, "Do not redact secret values from the config output")
 }
 
 // Run the verify command
 func Run(cmd *cobra.Command, _ []string) {
 	URL, _ := cmd.Flags().GetString("url")
+	showSecrets, _ := cmd.Flags().GetBool("show-secrets")
 	sr = router.ServiceRouter{}
 
 	service, err := sr.Locate(URL)
@@ -39,8 +45,28 @@ func Run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	// Services that log in to their server are verified by logging in, so that invalid credentials are reported here
+	// instead of when sending the first message
+	if verifier, ok := service.(types.Verifier); ok {
+		if err := verifier.Verify(context.Background()); err != nil {
+			fmt.Printf("error verifying credentials: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	config := format.GetServiceConfig(service)
+	if config == nil {
+		fmt.Println("the URL is valid, but the service does not expose its config")
+		return
+	}
 	configNode := format.GetConfigFormat(config)
+	if !showSecrets {
+		format.RedactSecrets(configNode)
+	}
 
 	_, _ = fmt.Fprint(color.Output, format.ColorFormatTree(configNode, true))
+
+	if keys := format.GetParamKeys(config); len(keys) > 0 {
+		fmt.Printf("\nParams that can be overridden per send: %s\n", strings.Join(keys, ", "))
+	}
 }
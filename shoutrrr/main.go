@@ -7,7 +7,9 @@ import (
 	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/docs"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/generate"
+	"github.com/containrrr/shoutrrr/shoutrrr/cmd/outbox"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/send"
+	"github.com/containrrr/shoutrrr/shoutrrr/cmd/serve"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/verify"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -25,6 +27,8 @@ func init() {
 	cmd.AddCommand(generate.Cmd)
 	cmd.AddCommand(send.Cmd)
 	cmd.AddCommand(docs.Cmd)
+	cmd.AddCommand(outbox.Cmd)
+	cmd.AddCommand(serve.Cmd)
 }
 
 func main() {